@@ -1,7 +1,7 @@
 package dawa
 
 import (
-	"io"
+	"context"
 	"strconv"
 )
 
@@ -23,14 +23,14 @@ type AdgangsAdresseQuery struct {
 //
 // See documentation at http://dawa.aws.dk/adgangsadressedok#adressesoegning
 func NewAdgangsAdresseQuery() *AdgangsAdresseQuery {
-	return &AdgangsAdresseQuery{queryGeoJSON: queryGeoJSON{query: query{host: DefaultHost, path: "/adgangsadresser"}}}
+	return DefaultClient.NewAdgangsAdresseQuery()
 }
 
 // NewAdgangsAdresseQuery returns a new query for 'adgangsadresser' objects for searching DAWA with autocomplete.
 //
 // See documentation at http://dawa.aws.dk/adgangsadressedok#adresseautocomplete
 func NewAdgangsAdresseComplete() *AdgangsAdresseQuery {
-	return &AdgangsAdresseQuery{queryGeoJSON: queryGeoJSON{query: query{host: DefaultHost, path: "/adgangsadresser/autocomplete"}}}
+	return DefaultClient.NewAdgangsAdresseComplete()
 }
 
 // GetAAID will return a single AdgangsAdresse with the specified ID.
@@ -61,46 +61,25 @@ func GetAAID(id string) (*AdgangsAdresse, error) {
 //			}
 //		}
 func (q AdgangsAdresseQuery) Iter() (*AdgangsAdresseIter, error) {
-	resp, err := q.NoFormat().Request()
-	if err != nil {
-		return nil, err
-	}
+	return q.IterContext(context.Background())
+}
 
-	iter, err := ImportAdgangsAdresserJSON(resp)
-	if err != nil {
-		return nil, err
-	}
-	iter.AddCloser(resp)
-	return iter, nil
+// IterContext will return an iterator like Iter().
+// If ctx is cancelled, the request is aborted and
+// the iterator will return the context error.
+func (q AdgangsAdresseQuery) IterContext(ctx context.Context) (*AdgangsAdresseIter, error) {
+	return iterQuery[AdgangsAdresse](ctx, q.query)
 }
 
 // All returns all results as an array.
 func (q AdgangsAdresseQuery) All() ([]AdgangsAdresse, error) {
-	resp, err := q.NoFormat().Request()
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Close()
-
-	ret := make([]AdgangsAdresse, 0)
-	iter, err := ImportAdgangsAdresserJSON(resp)
-	if err != nil {
-		return nil, err
-	}
-
-	for {
-		a, err := iter.Next()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return nil, err
-		}
-		if a != nil {
-			ret = append(ret, *a)
-		}
-	}
-	return ret, nil
+	return q.AllContext(context.Background())
+}
+
+// AllContext returns all results as an array like All().
+// If ctx is cancelled, the request is aborted and the context error is returned.
+func (q AdgangsAdresseQuery) AllContext(ctx context.Context) ([]AdgangsAdresse, error) {
+	return allQuery[AdgangsAdresse](ctx, q.query)
 }
 
 // First will return the first result from a query.
@@ -108,22 +87,48 @@ func (q AdgangsAdresseQuery) All() ([]AdgangsAdresse, error) {
 //
 // Will return (nil, io.EOF) if there is no results.
 func (q AdgangsAdresseQuery) First() (*AdgangsAdresse, error) {
-	resp, err := q.NoFormat().Request()
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Close()
+	return q.FirstContext(context.Background())
+}
+
+// FirstContext will return the first result from a query like First().
+// If ctx is cancelled, the request is aborted and the context error is returned.
+func (q AdgangsAdresseQuery) FirstContext(ctx context.Context) (*AdgangsAdresse, error) {
+	return firstQuery[AdgangsAdresse](ctx, q.query)
+}
 
-	iter, err := ImportAdgangsAdresserJSON(resp)
-	if err != nil {
-		return nil, err
-	}
+// GeoJSONTyped will return the results as GeoJSON features with typed properties and geometry.
+// The features are decoded as they are read, so large results are not kept in memory.
+// Close the iterator if you stop reading before all features have been returned.
+func (q AdgangsAdresseQuery) GeoJSONTyped() (*Iter[Feature[AdgangsAdresse]], error) {
+	return q.GeoJSONTypedContext(context.Background())
+}
 
-	a, err := iter.Next()
-	if err != nil {
-		return nil, err
-	}
-	return a, nil
+// GeoJSONTypedContext will return the features like GeoJSONTyped().
+// If ctx is cancelled, the request is aborted and
+// the iterator will return the context error.
+func (q AdgangsAdresseQuery) GeoJSONTypedContext(ctx context.Context) (*Iter[Feature[AdgangsAdresse]], error) {
+	return q.GeoJSONTypedOptions(ctx, CSVOptions{})
+}
+
+// GeoJSONTypedOptions will return the features like GeoJSONTypedContext().
+// The properties are parsed with the supplied options, like ImportCSV.
+// Properties that cannot be parsed stop the iterator with an *ImportError,
+// unless opts.OnError skips the feature.
+func (q AdgangsAdresseQuery) GeoJSONTypedOptions(ctx context.Context, opts CSVOptions) (*Iter[Feature[AdgangsAdresse]], error) {
+	return geoJSONQuery[AdgangsAdresse](ctx, q.query, opts)
+}
+
+// Paginate will make Iter() and All() fetch results page by page.
+// A new page is requested when all results of the previous page have been read.
+// Paging stops when a page contains less than pageSize results.
+// If maxResults is > 0, no more than maxResults will be returned.
+//
+// Any 'side' and 'per_side' parameters will be replaced when paginating.
+//
+// See http://dawa.aws.dk/generelt#paginering
+func (q *AdgangsAdresseQuery) Paginate(pageSize, maxResults int) *AdgangsAdresseQuery {
+	q.paging(pageSize, maxResults)
+	return q
 }
 
 // Q will add a parameter for 'q' to the AdgangsAdresseQuery.
@@ -276,41 +281,56 @@ func (q *AdgangsAdresseQuery) Esrejendomsnr(s ...string) *AdgangsAdresseQuery {
 // Srid will add a parameter for 'srid' to the AdgangsAdresseQuery.
 //
 // Angiver SRID for det koordinatsystem, som geospatiale parametre er angivet i. Default er 4326 (WGS84).
+// Points given to Polygon and Circle are transformed to this koordinatsystem, so it must be set before them.
+// Setting another SRID after them makes the query fail.
 //
 // See documentation at http://dawa.aws.dk/adgangsadressedok#adressesoegning
-func (q *AdgangsAdresseQuery) Srid(s string) *AdgangsAdresseQuery {
-	q.add(&textQuery{Name: "srid", Values: []string{s}, Multi: false, Null: false})
+func (q *AdgangsAdresseQuery) Srid(s SRID) *AdgangsAdresseQuery {
+	q.addSrid(s)
 	return q
 }
 
 // Polygon will add a parameter for 'polygon' to the AdgangsAdresseQuery.
 //
 // Find de adresser, som ligger indenfor det angivne polygon.
-// Polygonet specificeres som et array af koordinater på samme måde som koordinaterne
+// Polygonet specificeres som en række ringe af koordinater på samme måde som koordinaterne
 // specificeres i GeoJSON's polygon.
 // Bemærk at polygoner skal være lukkede, dvs. at første og sidste koordinat skal være identisk.
-// Som koordinatsystem kan anvendes (ETRS89/UTM32 eller) WGS84/geografisk.
-// Dette angives vha. srid parameteren, se ovenover.
-// Eksempel: Polygon("[[[10.3,55.3],[10.4,55.3],[10.4,55.31],[10.4,55.31],[10.3,55.3]]]")
+// Som koordinatsystem kan anvendes ETRS89/UTM32 eller WGS84/geografisk.
+// If Srid has not been set, the SRID of the first point is used, and all points are transformed to it.
+//
+// The rings are checked with ValidatePolygon. If they are invalid, a warning is added,
+// and the query will return the warning as an error instead of sending the request.
+// Example:
+//			ring := []dawa.Point{dawa.WGS84Point(10.3, 55.3), dawa.WGS84Point(10.4, 55.3), dawa.WGS84Point(10.4, 55.31), dawa.WGS84Point(10.3, 55.3)}
+//			q.Polygon(ring)
 //
 // See documentation at http://dawa.aws.dk/adgangsadressedok#adressesoegning
-func (q *AdgangsAdresseQuery) Polygon(s string) *AdgangsAdresseQuery {
-	q.add(&textQuery{Name: "polygon", Values: []string{s}, Multi: false, Null: false})
+func (q *AdgangsAdresseQuery) Polygon(rings ...[]Point) *AdgangsAdresseQuery {
+	q.addPolygon(rings)
 	return q
 }
 
-// Cirkel will add a parameter for 'cirkel' to the AdgangsAdresseQuery.
+// Circle will add a parameter for 'cirkel' to the AdgangsAdresseQuery.
+//
+// Find de adresser, som ligger indenfor den cirkel angivet af koordinatet center og radius.
+// Som koordinatsystem kan anvendes ETRS89/UTM32 eller WGS84/geografisk.
+// If Srid has not been set, the SRID of center is used.
 //
-// Find de adresser, som ligger indenfor den cirkel angivet af koordinatet (x,y) og radius r.
-// Som koordinatsystem kan anvendes (ETRS89/UTM32 eller) WGS84/geografisk.
-// Radius angives i meter. Cirkel("{x},{y},{r}")
+// The values are checked with ValidateCircle. If they are invalid, a warning is added,
+// and the query will return the warning as an error instead of sending the request.
 //
 // See documentation at http://dawa.aws.dk/adgangsadressedok#adressesoegning
-func (q *AdgangsAdresseQuery) Cirkel(s string) *AdgangsAdresseQuery {
-	q.add(&textQuery{Name: "cirkel", Values: []string{s}, Multi: false, Null: false})
+func (q *AdgangsAdresseQuery) Circle(center Point, radiusMeters float64) *AdgangsAdresseQuery {
+	q.addCircle(center, radiusMeters)
 	return q
 }
 
+// Cirkel is the same as Circle, named after the DAWA parameter.
+func (q *AdgangsAdresseQuery) Cirkel(center Point, radiusMeters float64) *AdgangsAdresseQuery {
+	return q.Circle(center, radiusMeters)
+}
+
 // Regionskode will add a parameter for 'regionskode' to the AdgangsAdresseQuery.
 //
 // Find de adresser som ligger indenfor regionen angivet ved regionkoden.
@@ -2,6 +2,7 @@ package dawa
 
 import (
 	"bytes"
+	"encoding/json"
 	"io"
 	"reflect"
 	"testing"
@@ -26,8 +27,12 @@ func TestImportAdgangsAdresserCSV(t *testing.T) {
 			Adgangspunkt: Adgangspunkt{
 				Kilde: 5,
 				Koordinater: []float64{
-					55.6720594006065,
 					12.5582458296225,
+					55.6720594006065,
+				},
+				Etrs89Koordinater: []float64{
+					723743.16,
+					6175322.16,
 				},
 				Nøjagtighed:     "A",
 				Tekniskstandard: "TD",
@@ -51,7 +56,7 @@ func TestImportAdgangsAdresserCSV(t *testing.T) {
 				Kode: "0101",
 				Navn: "København",
 			},
-			Kvh:        "",
+			Kvh:        "01010004__3A",
 			Matrikelnr: "377",
 			Opstillingskreds: OpstillingskredsRef{
 				Href: "",
@@ -462,3 +467,39 @@ func TestImportAdgangsAdresserJSON(t *testing.T) {
 		t.Fatalf("ImportAdgangsAdresserJSON: Expected io.EOF, got:%v", err)
 	}
 }
+
+func TestAdgangspunktKoordinaterOrder(t *testing.T) {
+	// CSV and JSON imports must fill Koordinater in the same order, [længde, bredde].
+	read := func(iter *AdgangsAdresseIter, err error) map[string][]float64 {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ret := make(map[string][]float64)
+		for a, err := range iter.All() {
+			if err != nil {
+				t.Fatal(err)
+			}
+			ret[a.ID] = a.Adgangspunkt.Koordinater
+		}
+		return ret
+	}
+	fromCSV := read(ImportAdgangsAdresserCSV(bytes.NewBufferString(adgangs_csv_data)))
+	fromJSON := read(ImportAdgangsAdresserJSON(bytes.NewBufferString(adgangs_json_input)))
+	if len(fromJSON) == 0 {
+		t.Fatal("No entries")
+	}
+	for id, j := range fromJSON {
+		if c := fromCSV[id]; !reflect.DeepEqual(c, j) {
+			t.Errorf("%s: CSV koordinater %v, JSON koordinater %v", id, c, j)
+		}
+	}
+
+	// Etrs89Koordinater is not part of the JSON encoding.
+	b, err := json.Marshal(Adgangspunkt{Koordinater: []float64{12.5, 55.6}, Etrs89Koordinater: []float64{723743.16, 6175322.16}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(b, []byte("etrs89")) {
+		t.Errorf("Unexpected JSON: %s", b)
+	}
+}
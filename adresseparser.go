@@ -0,0 +1,398 @@
+package dawa
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrNoVejnavn is returned when no vejnavn can be found in a free-text address.
+var ErrNoVejnavn = errors.New("dawa: no vejnavn found in address")
+
+// etageNames contains the accepted names of etager, that are not numbers.
+var etageNames = map[string]string{
+	"st":       "st",
+	"stuen":    "st",
+	"stue":     "st",
+	"kl":       "kl",
+	"kld":      "kl",
+	"kælder":   "kl",
+	"kælderen": "kl",
+}
+
+// dørNames contains the accepted names of døre, that are not numbers.
+var dørNames = map[string]string{
+	"tv":      "tv",
+	"th":      "th",
+	"mf":      "mf",
+	"venstre": "tv",
+	"højre":   "th",
+	"midt":    "mf",
+}
+
+// abbreviations are expanded before comparing names.
+var abbreviations = map[string]string{
+	"kbh":  "københavn",
+	"frb":  "frederiksberg",
+	"frbg": "frederiksberg",
+	"kgs":  "kongens",
+	"skt":  "sankt",
+	"st":   "sankt",
+	"gl":   "gammel",
+	"n":    "nord",
+	"s":    "syd",
+	"ø":    "øst",
+	"v":    "vest",
+}
+
+var (
+	// "1.tv", "st.th" and "2.sal" are split after the dot.
+	reEtageDot = regexp.MustCompile(`(?i)(^|[\s,])(st|kl|[0-9]{1,2})\.([^\s,])`)
+	reHusnr    = regexp.MustCompile(`^[0-9]{1,4}[a-zæøå]?$`)
+	reDør      = regexp.MustCompile(`^[0-9]{1,4}[a-zæøå]?$|^[a-zæøå]?[0-9]{1,4}$`)
+)
+
+// ParseAdresse will split a free-text address into its parts,
+// for example "Rødkildevej 46, 1. tv, 2400 Kbh NV".
+//
+// Commas are optional, and the common ways of writing etage and dør
+// are understood, like "st.", "stuen", "kl", "1. sal", "tv", "th" and "mf".
+// Etage and dør are returned in the form used by DAWA, for example "st" and "tv".
+//
+// The result is a best-effort guess. If no vejnavn can be found ErrNoVejnavn is returned.
+func ParseAdresse(s string) (*DatavaskFelter, error) {
+	toks := parseTokens(s)
+	var ret DatavaskFelter
+
+	// Postnummer is the last 4 digit number, followed by the postnummer name.
+	for i := len(toks) - 1; i > 0; i-- {
+		t := strings.TrimPrefix(plainToken(toks[i]), "dk-")
+		if len(t) != 4 || !isDigits(t) {
+			if toks[i] == "," || strings.ContainsAny(toks[i], "0123456789") {
+				break
+			}
+			continue
+		}
+		ret.Postnr = t
+		ret.Postnrnavn = joinTokens(toks[i+1:])
+		toks = toks[:i]
+		if len(toks) > 0 && plainToken(toks[len(toks)-1]) == "dk" {
+			toks = toks[:len(toks)-1]
+		}
+		break
+	}
+
+	// Vejnavn is everything before the first husnr or comma.
+	i := 0
+	for ; i < len(toks) && toks[i] != ","; i++ {
+		if i > 0 && reHusnr.MatchString(plainToken(toks[i])) {
+			break
+		}
+	}
+	ret.Vejnavn = joinTokens(toks[:i])
+	if ret.Vejnavn == "" || (i == 1 && reHusnr.MatchString(plainToken(toks[0]))) {
+		return nil, ErrNoVejnavn
+	}
+	toks = toks[i:]
+	if len(toks) > 0 && toks[0] != "," {
+		ret.Husnr = strings.ToUpper(plainToken(toks[0]))
+		toks = toks[1:]
+		// Allow a space before the letter, "12 B".
+		if len(toks) > 0 && isDigits(ret.Husnr) {
+			t := plainToken(toks[0])
+			if r, n := utf8.DecodeRuneInString(t); n == len(t) && unicode.IsLetter(r) && dørNames[t] == "" {
+				ret.Husnr += strings.ToUpper(t)
+				toks = toks[1:]
+			}
+		}
+	}
+
+	// Etage and dør follow the husnr, anything else is supplerende bynavn.
+	var suppl []string
+	for i := 0; i < len(toks); i++ {
+		raw := toks[i]
+		t := plainToken(raw)
+		switch {
+		case raw == ",":
+			if len(suppl) > 0 && suppl[len(suppl)-1] != "," {
+				suppl = append(suppl, ",")
+			}
+			continue
+		case len(suppl) == 0 && ret.Etage == "" && ret.Dør == "" && etage(t) != "":
+			ret.Etage = etage(t)
+			if i+1 < len(toks) && plainToken(toks[i+1]) == "sal" {
+				i++
+			}
+			continue
+		case len(suppl) == 0 && ret.Dør == "" && dørNames[t] != "":
+			ret.Dør = dørNames[t]
+			continue
+		case len(suppl) == 0 && ret.Dør == "" && ret.Etage != "" && reDør.MatchString(t):
+			ret.Dør = t
+			continue
+		}
+		suppl = append(suppl, raw)
+	}
+	if len(suppl) > 0 && suppl[len(suppl)-1] == "," {
+		suppl = suppl[:len(suppl)-1]
+	}
+	ret.SupplerendeBynavn = joinTokens(suppl)
+	return &ret, nil
+}
+
+// parseTokens splits s into words and commas.
+func parseTokens(s string) []string {
+	s = reEtageDot.ReplaceAllString(s, "$1$2. $3")
+	var ret []string
+	for _, f := range strings.Fields(s) {
+		for f != "" {
+			i := strings.IndexByte(f, ',')
+			if i < 0 {
+				ret = append(ret, f)
+				break
+			}
+			if i > 0 {
+				ret = append(ret, f[:i])
+			}
+			ret = append(ret, ",")
+			f = f[i+1:]
+		}
+	}
+	return ret
+}
+
+// plainToken returns the token in lower case without a trailing dot.
+func plainToken(s string) string {
+	return strings.ToLower(strings.TrimSuffix(s, "."))
+}
+
+// joinTokens joins the tokens with spaces, and commas without a space before.
+func joinTokens(toks []string) string {
+	var sb strings.Builder
+	for _, t := range toks {
+		if t == "," {
+			if sb.Len() > 0 {
+				sb.WriteByte(',')
+			}
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(t)
+	}
+	return strings.Trim(sb.String(), ", ")
+}
+
+// etage returns the DAWA form of an etage, or "" if t isn't an etage.
+func etage(t string) string {
+	if e, ok := etageNames[t]; ok {
+		return e
+	}
+	if len(t) == 3 && strings.HasPrefix(t, "kl") && t[2] >= '1' && t[2] <= '9' {
+		return t
+	}
+	if len(t) <= 2 && isDigits(t) {
+		return strings.TrimLeft(t, "0")
+	}
+	return ""
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// FuzzyMatch is a candidate for a free-text address.
+type FuzzyMatch struct {
+	Adresse   Adresse
+	Afstand   int               // Samlet afstand mellem den søgte og den fundne adresse.
+	Forskelle DatavaskForskelle // Afstand for de enkelte felter.
+}
+
+// RankAdresser will rank the candidates by how well they match the parsed address.
+// The distance of each field is the edit distance between the values,
+// ignoring case and expanding common abbreviations like "Kbh" and "Gl.".
+// Supplerende bynavn, postnummer and postnummer name are only compared if present in the parsed address.
+//
+// The best matches are returned first.
+func RankAdresser(p DatavaskFelter, candidates []Adresse) []FuzzyMatch {
+	ret := make([]FuzzyMatch, len(candidates))
+	for i, a := range candidates {
+		aa := &a.Adgangsadresse
+		f := DatavaskForskelle{
+			Vejnavn: nameDistance(p.Vejnavn, aa.Vejstykke.Navn),
+			Husnr:   editDistance(strings.ToLower(p.Husnr), strings.ToLower(aa.Husnr)),
+			Etage:   editDistance(p.Etage, a.Etage),
+			Dør:     editDistance(p.Dør, a.Dør),
+		}
+		if p.Postnr != "" {
+			f.Postnr = editDistance(p.Postnr, aa.Postnummer.Nr)
+		}
+		if p.SupplerendeBynavn != "" {
+			f.SupplerendeBynavn = nameDistance(p.SupplerendeBynavn, aa.SupplerendeBynavn)
+		}
+		if p.Postnrnavn != "" {
+			f.Postnrnavn = nameDistance(p.Postnrnavn, aa.Postnummer.Navn)
+		}
+		ret[i] = FuzzyMatch{
+			Adresse:   a,
+			Afstand:   f.Vejnavn + f.Husnr + f.Etage + f.Dør + f.SupplerendeBynavn + f.Postnr + f.Postnrnavn,
+			Forskelle: f,
+		}
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		if ret[i].Afstand != ret[j].Afstand {
+			return ret[i].Afstand < ret[j].Afstand
+		}
+		return adresseLess(&ret[i].Adresse, &ret[j].Adresse)
+	})
+	return ret
+}
+
+// nameDistance returns the edit distance between two names,
+// after expanding abbreviations.
+func nameDistance(a, b string) int {
+	return editDistance(normalizeName(a), normalizeName(b))
+}
+
+// normalizeName returns the name in lower case with abbreviations expanded.
+func normalizeName(s string) string {
+	words := tokenize(s)
+	for i, w := range words {
+		if e, ok := abbreviations[w]; ok {
+			words[i] = e
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// matchParams returns the parameters used to find candidates for a parsed address.
+// Each set of parameters is tried in turn, until one returns candidates.
+func matchParams(p *DatavaskFelter) []map[string][]string {
+	var ret []map[string][]string
+	if p.Postnr != "" && p.Husnr != "" {
+		ret = append(ret, map[string][]string{"postnr": {p.Postnr}, "husnr": {p.Husnr}})
+	}
+	if p.Husnr != "" {
+		ret = append(ret, map[string][]string{"vejnavn": {p.Vejnavn}, "husnr": {p.Husnr}})
+	}
+	if p.Postnr != "" {
+		ret = append(ret, map[string][]string{"postnr": {p.Postnr}, "vejnavn": {p.Vejnavn}})
+	}
+	if p.Husnr == "" && p.Postnr == "" {
+		ret = append(ret, map[string][]string{"vejnavn": {p.Vejnavn}})
+	}
+	return ret
+}
+
+// rankLimit will rank the candidates and return at most limit results.
+func rankLimit(p *DatavaskFelter, candidates []Adresse, limit int) []FuzzyMatch {
+	ret := RankAdresser(*p, candidates)
+	if limit > 0 && len(ret) > limit {
+		ret = ret[:limit]
+	}
+	return ret
+}
+
+// MatchAdresse will parse the free-text address, find candidates and rank them.
+// If limit > 0, at most limit results are returned.
+//
+// Candidates are found using the postnummer, vejnavn and husnr of the address,
+// so misspellings of one of these are tolerated.
+// See ParseAdresse and RankAdresser.
+func MatchAdresse(s string, limit int) ([]FuzzyMatch, error) {
+	return DefaultClient.MatchAdresseContext(context.Background(), s, limit)
+}
+
+// MatchAdresseContext will parse the free-text address, find candidates and rank them.
+// If limit > 0, at most limit results are returned.
+//
+// Candidates are found using the postnummer, vejnavn and husnr of the address,
+// so misspellings of one of these are tolerated.
+// See ParseAdresse and RankAdresser.
+func MatchAdresseContext(ctx context.Context, s string, limit int) ([]FuzzyMatch, error) {
+	return DefaultClient.MatchAdresseContext(ctx, s, limit)
+}
+
+// MatchAdresse will parse the free-text address, find candidates using this client and rank them.
+// If limit > 0, at most limit results are returned.
+func (c *Client) MatchAdresse(s string, limit int) ([]FuzzyMatch, error) {
+	return c.MatchAdresseContext(context.Background(), s, limit)
+}
+
+// MatchAdresseContext will parse the free-text address, find candidates using this client and rank them.
+// If limit > 0, at most limit results are returned.
+func (c *Client) MatchAdresseContext(ctx context.Context, s string, limit int) ([]FuzzyMatch, error) {
+	p, err := ParseAdresse(s)
+	if err != nil {
+		return nil, err
+	}
+	for _, params := range matchParams(p) {
+		q := c.NewAdresseQuery()
+		for name, values := range params {
+			q.add(&textQuery{Name: name, Values: values, Multi: true})
+		}
+		candidates, err := q.AllContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(candidates) > 0 {
+			return rankLimit(p, candidates, limit), nil
+		}
+	}
+	return []FuzzyMatch{}, nil
+}
+
+// MatchAdresse will parse the free-text address, find candidates in the index and rank them.
+// If limit > 0, at most limit results are returned.
+//
+// Candidates are found using the postnummer, vejnavn and husnr of the address,
+// so misspellings of one of these are tolerated.
+// See ParseAdresse and RankAdresser.
+func (x *Index) MatchAdresse(s string, limit int) ([]FuzzyMatch, error) {
+	p, err := ParseAdresse(s)
+	if err != nil {
+		return nil, err
+	}
+	for _, params := range matchParams(p) {
+		candidates := x.adresser.find(params, 0)
+		if len(candidates) > 0 {
+			return rankLimit(p, candidates, limit), nil
+		}
+	}
+	return []FuzzyMatch{}, nil
+}
@@ -0,0 +1,224 @@
+package dawa
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseAdresse(t *testing.T) {
+	tests := []struct {
+		in     string
+		expect DatavaskFelter
+	}{
+		{
+			in:     "Rødkildevej 46, 1. tv, 2400 Kbh NV",
+			expect: DatavaskFelter{Vejnavn: "Rødkildevej", Husnr: "46", Etage: "1", Dør: "tv", Postnr: "2400", Postnrnavn: "Kbh NV"},
+		},
+		{
+			in:     "Rødkildevej 46 1.tv 2400 København NV",
+			expect: DatavaskFelter{Vejnavn: "Rødkildevej", Husnr: "46", Etage: "1", Dør: "tv", Postnr: "2400", Postnrnavn: "København NV"},
+		},
+		{
+			in:     "H.C. Andersens Boulevard 27 st.th., DK-1553 København V",
+			expect: DatavaskFelter{Vejnavn: "H.C. Andersens Boulevard", Husnr: "27", Etage: "st", Dør: "th", Postnr: "1553", Postnrnavn: "København V"},
+		},
+		{
+			in:     "A Hansensvej 6 b, Vråby, 6792 Rømø",
+			expect: DatavaskFelter{Vejnavn: "A Hansensvej", Husnr: "6B", SupplerendeBynavn: "Vråby", Postnr: "6792", Postnrnavn: "Rømø"},
+		},
+		{
+			in:     "Skt. Annæ Plads 10, 2. sal, 1250 København K",
+			expect: DatavaskFelter{Vejnavn: "Skt. Annæ Plads", Husnr: "10", Etage: "2", Postnr: "1250", Postnrnavn: "København K"},
+		},
+		{
+			in:     "Vestergade 12A kælder",
+			expect: DatavaskFelter{Vejnavn: "Vestergade", Husnr: "12A", Etage: "kl"},
+		},
+		{
+			in:     "Vestergade 12, kl2 mf",
+			expect: DatavaskFelter{Vejnavn: "Vestergade", Husnr: "12", Etage: "kl2", Dør: "mf"},
+		},
+		{
+			in:     "Vestergade 12, 3, 14, DK 8000 Aarhus C",
+			expect: DatavaskFelter{Vejnavn: "Vestergade", Husnr: "12", Etage: "3", Dør: "14", Postnr: "8000", Postnrnavn: "Aarhus C"},
+		},
+		{
+			in:     "Vestergade 12 th",
+			expect: DatavaskFelter{Vejnavn: "Vestergade", Husnr: "12", Dør: "th"},
+		},
+		{
+			in:     "Bygaden, Lille Vråby",
+			expect: DatavaskFelter{Vejnavn: "Bygaden", SupplerendeBynavn: "Lille Vråby"},
+		},
+		{
+			in:     "1. Maj Vej 5",
+			expect: DatavaskFelter{Vejnavn: "1. Maj Vej", Husnr: "5"},
+		},
+		{
+			in:     "  Bygaden  ",
+			expect: DatavaskFelter{Vejnavn: "Bygaden"},
+		},
+	}
+	for _, test := range tests {
+		got, err := ParseAdresse(test.in)
+		if err != nil {
+			t.Errorf("ParseAdresse(%q): %v", test.in, err)
+			continue
+		}
+		if *got != test.expect {
+			t.Errorf("ParseAdresse(%q):\ngot      %+v\nexpected %+v", test.in, *got, test.expect)
+		}
+	}
+
+	for _, in := range []string{"", " , ", "12, 2400 København NV"} {
+		if _, err := ParseAdresse(in); err != ErrNoVejnavn {
+			t.Errorf("ParseAdresse(%q): expected ErrNoVejnavn, got %v", in, err)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		expect int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"rødkildevej", "rødkildevej", 0},
+		{"rødkildevej", "rødkilevej", 1},
+		{"ålbæk", "aalbæk", 2},
+	}
+	for _, test := range tests {
+		if got := editDistance(test.a, test.b); got != test.expect {
+			t.Errorf("editDistance(%q, %q): got %d, expected %d", test.a, test.b, got, test.expect)
+		}
+	}
+	if got := nameDistance("Kbh NV", "København NV"); got != 0 {
+		t.Errorf("nameDistance: got %d, expected 0", got)
+	}
+}
+
+func fuzzyTestAdresser() []Adresse {
+	aa := AdgangsAdresse{
+		ID:         "aa-1",
+		Husnr:      "46",
+		Vejstykke:  VejstykkeRef{Navn: "Rødkildevej", Kode: "0001"},
+		Postnummer: PostnummerRef{Nr: "2400", Navn: "København NV"},
+	}
+	other := aa
+	other.ID, other.Vejstykke.Navn = "aa-2", "Rødovrevej"
+	return []Adresse{
+		{ID: "a-1", Adgangsadresse: aa, Etage: "st", Dør: "tv"},
+		{ID: "a-2", Adgangsadresse: aa, Etage: "1", Dør: "th"},
+		{ID: "a-3", Adgangsadresse: aa, Etage: "1", Dør: "tv"},
+		{ID: "a-4", Adgangsadresse: other, Etage: "1", Dør: "tv"},
+	}
+}
+
+func TestRankAdresser(t *testing.T) {
+	p, err := ParseAdresse("Rødkilevej 46, 1. tv, 2400 Kbh NV")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := RankAdresser(*p, fuzzyTestAdresser())
+	if len(res) != 4 {
+		t.Fatalf("Expected 4 results, got %d", len(res))
+	}
+	expect := []string{"a-3", "a-2", "a-1", "a-4"}
+	for i, id := range expect {
+		if res[i].Adresse.ID != id {
+			t.Errorf("Result %d: got %s, expected %s", i, res[i].Adresse.ID, id)
+		}
+	}
+	if f := res[0].Forskelle; res[0].Afstand != 1 || f.Vejnavn != 1 || f.Postnrnavn != 0 {
+		t.Errorf("Unexpected first result: %+v", res[0])
+	}
+}
+
+func TestIndexMatchAdresse(t *testing.T) {
+	idx := NewIndex()
+	idx.AddAdresser(fuzzyTestAdresser()...)
+
+	// Misspelt vejnavn, found using postnr and husnr.
+	res, err := idx.MatchAdresse("Rødkilevej 46, 1 tv, 2400", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 2 || res[0].Adresse.ID != "a-3" || res[1].Adresse.ID != "a-2" {
+		t.Fatalf("Unexpected result: %+v", res)
+	}
+
+	// Wrong postnr, found using vejnavn and husnr.
+	res, err = idx.MatchAdresse("Rødkildevej 46 st tv 2300 København S", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 3 || res[0].Adresse.ID != "a-1" || res[0].Forskelle.Postnr != 1 {
+		t.Fatalf("Unexpected result: %+v", res)
+	}
+
+	res, err = idx.MatchAdresse("Nowhere 1", 0)
+	if err != nil || len(res) != 0 {
+		t.Fatalf("Unexpected result: %+v, %v", res, err)
+	}
+	if _, err = idx.MatchAdresse("", 0); err != ErrNoVejnavn {
+		t.Fatalf("Expected ErrNoVejnavn, got %v", err)
+	}
+}
+
+func TestClientMatchAdresse(t *testing.T) {
+	var queries []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		var res []Adresse
+		q := r.URL.Query()
+		// Only answer the vejnavn and husnr query.
+		if q.Get("vejnavn") == "Rødkildevej" && q.Get("husnr") == "46" {
+			res = fuzzyTestAdresser()[:3]
+		}
+		b, _ := json.Marshal(res)
+		w.Write(b)
+	}))
+	defer ts.Close()
+	c := &Client{BaseURL: ts.URL}
+
+	res, err := c.MatchAdresse("Rødkildevej 46, 1. th, 2300 Kbh NV", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].Adresse.ID != "a-2" {
+		t.Fatalf("Unexpected result: %+v", res)
+	}
+	if len(queries) != 2 {
+		t.Fatalf("Expected 2 queries, got %v", queries)
+	}
+
+	queries = nil
+	res, err = c.MatchAdresse("Nowhere 1, 2400", 1)
+	if err != nil || len(res) != 0 {
+		t.Fatalf("Unexpected result: %+v, %v", res, err)
+	}
+	if len(queries) != 3 {
+		t.Fatalf("Expected 3 queries, got %v", queries)
+	}
+
+	// Check the results can be used with an index.
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(fuzzyTestAdresser())
+	iter, err := ImportAdresserJSON(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx := NewIndex()
+	if err := idx.LoadAdresser(iter); err != nil {
+		t.Fatal(err)
+	}
+	if res, _ := idx.MatchAdresse("Rødkildevej 46, 1. th", 1); len(res) != 1 || res[0].Adresse.ID != "a-2" {
+		t.Fatalf("Unexpected result: %+v", res)
+	}
+}
@@ -41,6 +41,8 @@ var multiParam = []string{`Mtest!"#222.%&=?`, "Seconday Param*"}
 var multiEncoded = `Mtest%21%22%23222.%25%26%3D%3F|Seconday+Param%2A`
 var intParam = 23453231
 var intEncoded = "23453231"
+var testRing = []Point{WGS84Point(10.3, 55.3), WGS84Point(10.4, 55.3), WGS84Point(10.4, 55.31), WGS84Point(10.3, 55.3)}
+var testRingEncoded = "%5B%5B%5B10.3%2C55.3%5D%2C%5B10.4%2C55.3%5D%2C%5B10.4%2C55.31%5D%2C%5B10.3%2C55.3%5D%5D%5D"
 
 var AdresseURL = []qb{
 	// No parameters.
@@ -48,7 +50,7 @@ var AdresseURL = []qb{
 	qb{NewAdresseComplete().URL(), DefaultHost + "/adresser/autocomplete"},
 
 	// Single parameter
-	qb{NewAdresseQuery().Cirkel(singleParam).URL(), DefaultHost + "/adresser?cirkel=" + singleEncoded + ""},
+	qb{NewAdresseQuery().Cirkel(WGS84Point(12.5, 55.6), 100).URL(), DefaultHost + "/adresser?cirkel=12.5%2C55.6%2C100"},
 	qb{NewAdresseQuery().Dør(multiParam...).URL(), DefaultHost + "/adresser?d%C3%B8r=" + multiEncoded + ""},
 	qb{NewAdresseQuery().Ejerlavkode(multiParam...).URL(), DefaultHost + "/adresser?ejerlavkode=" + multiEncoded + ""},
 	qb{NewAdresseQuery().Esrejendomsnr(multiParam...).URL(), DefaultHost + "/adresser?esrejendomsnr=" + multiEncoded + ""},
@@ -62,14 +64,14 @@ var AdresseURL = []qb{
 	qb{NewAdresseQuery().Opstillingskredskode(multiParam...).URL(), DefaultHost + "/adresser?opstillingskredskode=" + multiEncoded + ""},
 	qb{NewAdresseQuery().PerSide(intParam).URL(), DefaultHost + "/adresser?per_side=" + intEncoded + ""},
 	qb{NewAdresseQuery().Politikredskode(multiParam...).URL(), DefaultHost + "/adresser?politikredskode=" + multiEncoded + ""},
-	qb{NewAdresseQuery().Polygon(singleParam).URL(), DefaultHost + "/adresser?polygon=" + singleEncoded + ""},
+	qb{NewAdresseQuery().Polygon(testRing).URL(), DefaultHost + "/adresser?polygon=" + testRingEncoded},
 	qb{NewAdresseQuery().Postnr(multiParam...).URL(), DefaultHost + "/adresser?postnr=" + multiEncoded + ""},
 	qb{NewAdresseQuery().Q(singleParam).URL(), DefaultHost + "/adresser?q=" + singleEncoded + ""},
 	qb{NewAdresseQuery().Regionskode(multiParam...).URL(), DefaultHost + "/adresser?regionskode=" + multiEncoded + ""},
 	qb{NewAdresseQuery().Retskredskode(multiParam...).URL(), DefaultHost + "/adresser?retskredskode=" + multiEncoded + ""},
 	qb{NewAdresseQuery().Side(intParam).URL(), DefaultHost + "/adresser?side=" + intEncoded + ""},
 	qb{NewAdresseQuery().Sognekode(multiParam...).URL(), DefaultHost + "/adresser?sognekode=" + multiEncoded + ""},
-	qb{NewAdresseQuery().Srid(singleParam).URL(), DefaultHost + "/adresser?srid=" + singleEncoded + ""},
+	qb{NewAdresseQuery().Srid(ETRS89UTM32).URL(), DefaultHost + "/adresser?srid=25832"},
 	qb{NewAdresseQuery().Status(intParam).URL(), DefaultHost + "/adresser?status=" + intEncoded + ""},
 	qb{NewAdresseQuery().SupplerendeBynavn(multiParam...).URL(), DefaultHost + "/adresser?supplerendebynavn=" + multiEncoded + ""},
 	qb{NewAdresseQuery().Vejkode(multiParam...).URL(), DefaultHost + "/adresser?vejkode=" + multiEncoded + ""},
@@ -77,8 +79,8 @@ var AdresseURL = []qb{
 	qb{NewAdresseQuery().Zonekode(multiParam...).URL(), DefaultHost + "/adresser?zonekode=" + multiEncoded + ""},
 
 	// Combined parameters
-	qb{NewAdresseQuery().Cirkel(singleParam).Etage(multiParam...).Husnr(multiParam...).Kvhx(singleParam).URL(),
-		DefaultHost + "/adresser?cirkel=" + singleEncoded + "&etage=" + multiEncoded + "&husnr=" + multiEncoded + "&kvhx=" + singleEncoded + ""},
+	qb{NewAdresseQuery().Cirkel(ETRS89Point(723743.16, 6175322.16), 50).Etage(multiParam...).Husnr(multiParam...).Kvhx(singleParam).URL(),
+		DefaultHost + "/adresser?srid=25832&cirkel=723743.16%2C6175322.16%2C50&etage=" + multiEncoded + "&husnr=" + multiEncoded + "&kvhx=" + singleEncoded + ""},
 
 	qb{NewAdresseQuery().ID(multiParam...).Dør(multiParam...).Kommunekode(multiParam...).Opstillingskredskode(multiParam...).URL(),
 		DefaultHost + "/adresser?id=" + multiEncoded + "&d%C3%B8r=" + multiEncoded + "&kommunekode=" + multiEncoded + "&opstillingskredskode=" + multiEncoded + ""},
@@ -86,8 +88,8 @@ var AdresseURL = []qb{
 	qb{NewAdresseQuery().Ejerlavkode(multiParam...).Matrikelnr(multiParam...).NoFormat().Postnr(multiParam...).PerSide(intParam).Politikredskode(multiParam...).URL(),
 		DefaultHost + "/adresser?ejerlavkode=" + multiEncoded + "&matrikelnr=" + multiEncoded + "&noformat=&postnr=" + multiEncoded + "&per_side=" + intEncoded + "&politikredskode=" + multiEncoded + ""},
 
-	qb{NewAdresseQuery().Esrejendomsnr(multiParam...).Polygon(singleParam).Q(singleParam).Regionskode(multiParam...).Retskredskode(multiParam...).URL(),
-		DefaultHost + "/adresser?esrejendomsnr=" + multiEncoded + "&polygon=" + singleEncoded + "&q=" + singleEncoded + "&regionskode=" + multiEncoded + "&retskredskode=" + multiEncoded + ""},
+	qb{NewAdresseQuery().Esrejendomsnr(multiParam...).Polygon(testRing).Q(singleParam).Regionskode(multiParam...).Retskredskode(multiParam...).URL(),
+		DefaultHost + "/adresser?esrejendomsnr=" + multiEncoded + "&polygon=" + testRingEncoded + "&q=" + singleEncoded + "&regionskode=" + multiEncoded + "&retskredskode=" + multiEncoded + ""},
 
 	// Parameter merging
 	qb{NewAdresseQuery().Vejkode(multiParam...).Zonekode(multiParam...).Vejkode("mergeme").URL(),
@@ -95,8 +97,8 @@ var AdresseURL = []qb{
 
 	// Merge non multi
 	// We expect the second Srid to be dropped
-	qb{NewAdresseQuery().Srid(singleParam).Zonekode(multiParam...).Srid("dropme").URL(),
-		DefaultHost + "/adresser?srid=" + singleEncoded + "&zonekode=" + multiEncoded + ""},
+	qb{NewAdresseQuery().Srid(ETRS89UTM32).Zonekode(multiParam...).Srid(WGS84).URL(),
+		DefaultHost + "/adresser?srid=25832&zonekode=" + multiEncoded + ""},
 }
 
 func TestAddresseQueryURL(t *testing.T) {
@@ -0,0 +1,576 @@
+package dawa
+
+import (
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Autocomplete is an offline autocomplete for adresser, adgangsadresser and postnumre.
+//
+// It can be loaded from the bulk downloads and will give the same kind of
+// suggestions as the autocomplete services, without any network calls.
+//
+// Search texts follow the rules of the 'q' parameter:
+// All words in the search text must match, the search is case-insensitive,
+// and the wildcard * is allowed at the end of each word.
+// As with the autocomplete services, the last word is always matched as a prefix,
+// unless the search text ends with a space.
+//
+// The words of the entries are kept in a sorted word list, with the entries containing
+// each word, so the words matching a prefix are found by a binary search.
+// The index is rebuilt on the first search after entries have been added.
+// Only the entries that are returned are copied.
+//
+// An Autocomplete is safe for concurrent use.
+//
+// Example:
+//			ac := dawa.NewAutocomplete()
+//			iter, _ := dawa.ImportAdresserCSV(f)
+//			err := ac.LoadAdresser(iter)
+//
+//			// Get suggestions for the text entered so far.
+//			res := ac.Complete("Hansensv", 10)
+type Autocomplete struct {
+	mu              sync.RWMutex
+	dirty           bool
+	adresser        textIndex[Adresse]
+	adgangsadresser textIndex[AdgangsAdresse]
+	postnumre       textIndex[Postnummer]
+	vejnavne        textIndex[string]
+	seenAA          map[string]struct{}
+	seenVej         map[string]struct{}
+}
+
+// The types of autocomplete results.
+const (
+	AutocompleteVejnavn        = "vejnavn"
+	AutocompleteAdgangsadresse = "adgangsadresse"
+	AutocompleteAdresse        = "adresse"
+)
+
+// AutocompleteResult is a suggestion returned by Autocomplete.Complete.
+type AutocompleteResult struct {
+	Type           string          // Typen af forslaget. "vejnavn", "adgangsadresse" eller "adresse".
+	Tekst          string          // Den tekst, der skal indsættes i søgefeltet, når forslaget vælges.
+	Forslagstekst  string          // Den tekst, der skal vises for forslaget.
+	Caretpos       int             // Placeringen af markøren i Tekst, når forslaget vælges.
+	Vejnavn        string          // Vejnavnet. Sat for alle typer.
+	AdgangsAdresse *AdgangsAdresse // Adgangsadressen. Sat for typen "adgangsadresse" og "adresse".
+	Adresse        *Adresse        // Adressen. Kun sat for typen "adresse".
+}
+
+// NewAutocomplete returns a new empty autocomplete.
+func NewAutocomplete() *Autocomplete {
+	return &Autocomplete{
+		adresser: textIndex[Adresse]{
+			text: func(a *Adresse) string { return a.Betegnelse() },
+			less: adresseLess,
+		},
+		adgangsadresser: textIndex[AdgangsAdresse]{
+			text: func(a *AdgangsAdresse) string { return a.Betegnelse() },
+			less: adgangsAdresseLess,
+		},
+		postnumre: textIndex[Postnummer]{
+			text: func(p *Postnummer) string { return p.Nr + " " + p.Navn },
+			less: func(a, b *Postnummer) bool { return a.Nr < b.Nr },
+		},
+		vejnavne: textIndex[string]{
+			text: func(s *string) string { return *s },
+			less: func(a, b *string) bool { return *a < *b },
+		},
+		seenAA:  make(map[string]struct{}),
+		seenVej: make(map[string]struct{}),
+	}
+}
+
+// AddAdresser will add the addresses to the autocomplete.
+// The adgangsadresser of the addresses are also added.
+func (ac *Autocomplete) AddAdresser(a ...Adresse) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	ac.adresser.add(a...)
+	for i := range a {
+		ac.addAdgangsAdresse(a[i].Adgangsadresse)
+	}
+	ac.dirty = true
+}
+
+// AddAdgangsAdresser will add the addresses to the autocomplete.
+func (ac *Autocomplete) AddAdgangsAdresser(a ...AdgangsAdresse) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	for _, aa := range a {
+		ac.addAdgangsAdresse(aa)
+	}
+	ac.dirty = true
+}
+
+// AddPostnumre will add the postnumre to the autocomplete.
+func (ac *Autocomplete) AddPostnumre(p ...Postnummer) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	ac.postnumre.add(p...)
+	ac.dirty = true
+}
+
+// LoadAdresser will add all addresses from the iterator to the autocomplete.
+// The iterator can be returned by ImportAdresserCSV, ImportAdresserJSON or a query.
+// The iterator is closed when the function returns.
+func (ac *Autocomplete) LoadAdresser(iter *AdresseIter) error {
+	return loadAutocomplete(iter, ac.AddAdresser)
+}
+
+// LoadAdgangsAdresser will add all addresses from the iterator to the autocomplete.
+// The iterator can be returned by ImportAdgangsAdresserCSV, ImportAdgangsAdresserJSON or a query.
+// The iterator is closed when the function returns.
+func (ac *Autocomplete) LoadAdgangsAdresser(iter *AdgangsAdresseIter) error {
+	return loadAutocomplete(iter, ac.AddAdgangsAdresser)
+}
+
+// LoadPostnumre will add all postnumre from the iterator to the autocomplete.
+// The iterator can be returned by ImportPostnumreJSON or a query.
+// The iterator is closed when the function returns.
+func (ac *Autocomplete) LoadPostnumre(iter *PostnummerIter) error {
+	return loadAutocomplete(iter, ac.AddPostnumre)
+}
+
+// loadAutocomplete will add all entries from iter using add.
+func loadAutocomplete[T any](iter *Iter[T], add func(...T)) error {
+	defer iter.Close()
+	const batch = 1000
+	items := make([]T, 0, batch)
+	for {
+		a, err := iter.Next()
+		if err == io.EOF {
+			add(items...)
+			return nil
+		}
+		if err != nil {
+			add(items...)
+			return err
+		}
+		items = append(items, *a)
+		if len(items) == batch {
+			add(items...)
+			items = items[:0]
+		}
+	}
+}
+
+// addAdgangsAdresse will add the adgangsadresse and its vejnavn,
+// unless an adgangsadresse with the same ID has been added.
+// ac.mu must be held.
+func (ac *Autocomplete) addAdgangsAdresse(a AdgangsAdresse) {
+	if a.ID != "" {
+		if _, ok := ac.seenAA[a.ID]; ok {
+			return
+		}
+		ac.seenAA[a.ID] = struct{}{}
+	}
+	ac.adgangsadresser.add(a)
+	if v := a.Vejstykke.Navn; v != "" {
+		if _, ok := ac.seenVej[v]; !ok {
+			ac.seenVej[v] = struct{}{}
+			ac.vejnavne.add(v)
+		}
+	}
+}
+
+// rlock will read lock the autocomplete, and build the indexes if needed.
+func (ac *Autocomplete) rlock() {
+	ac.mu.RLock()
+	for ac.dirty {
+		ac.mu.RUnlock()
+		ac.mu.Lock()
+		if ac.dirty {
+			ac.adresser.build()
+			ac.adgangsadresser.build()
+			ac.postnumre.build()
+			ac.vejnavne.build()
+			ac.dirty = false
+		}
+		ac.mu.Unlock()
+		ac.mu.RLock()
+	}
+}
+
+// Adresser returns adresser matching the search text, like NewAdresseComplete().Q(q).
+// If limit > 0, at most limit entries are returned.
+//
+// Der søges i vejnavn, husnr, etage, dør, supplerende bynavn, postnr og postnummerets navn.
+func (ac *Autocomplete) Adresser(q string, limit int) []Adresse {
+	ac.rlock()
+	defer ac.mu.RUnlock()
+	return ac.adresser.get(ac.adresser.search(parseQ(q), limit))
+}
+
+// AdgangsAdresser returns adgangsadresser matching the search text, like NewAdgangsAdresseComplete().Q(q).
+// If limit > 0, at most limit entries are returned.
+//
+// Der søges i vejnavn, husnr, supplerende bynavn, postnr og postnummerets navn.
+func (ac *Autocomplete) AdgangsAdresser(q string, limit int) []AdgangsAdresse {
+	ac.rlock()
+	defer ac.mu.RUnlock()
+	return ac.adgangsadresser.get(ac.adgangsadresser.search(parseQ(q), limit))
+}
+
+// Postnumre returns postnumre matching the search text, like NewPostnrComplete().Q(q).
+// If limit > 0, at most limit entries are returned.
+//
+// Der søges i postnummer og postnummerets navn.
+func (ac *Autocomplete) Postnumre(q string, limit int) []Postnummer {
+	ac.rlock()
+	defer ac.mu.RUnlock()
+	return ac.postnumre.get(ac.postnumre.search(parseQ(q), limit))
+}
+
+// Vejnavne returns the distinct vejnavne matching the search text.
+// If limit > 0, at most limit entries are returned.
+func (ac *Autocomplete) Vejnavne(q string, limit int) []string {
+	ac.rlock()
+	defer ac.mu.RUnlock()
+	return ac.vejnavne.get(ac.vejnavne.search(parseQ(q), limit))
+}
+
+// Complete returns suggestions for the search text, following the flow of
+// the autocomplete service:
+//
+// While the text matches more than one vejnavn, or a vejnavn that has not been
+// selected, vejnavne are suggested.
+// A vejnavn is selected when the text is the vejnavn followed by a space.
+//
+// Then adgangsadresser matching the text are suggested.
+//
+// When the text matches a single adgangsadresse with several adresser,
+// or only matches adresser, the adresser are suggested.
+//
+// If limit > 0, at most limit suggestions are returned.
+func (ac *Autocomplete) Complete(q string, limit int) []AutocompleteResult {
+	words := parseQ(q)
+	if len(words) == 0 {
+		return nil
+	}
+	ac.rlock()
+	defer ac.mu.RUnlock()
+
+	// The positions are in the order of the results,
+	// so only the entries that are returned are copied.
+	vp := ac.vejnavne.search(words, 0)
+	selected := ""
+	for _, p := range vp {
+		if v := ac.vejnavne.items[p]; vejnavnSelected(q, v) {
+			selected = v
+			break
+		}
+	}
+	if len(vp) > 0 && selected == "" {
+		veje := ac.vejnavne.get(limitInts(vp, limit))
+		ret := make([]AutocompleteResult, len(veje))
+		for i, v := range veje {
+			ret[i] = AutocompleteResult{
+				Type:          AutocompleteVejnavn,
+				Tekst:         v + " ",
+				Forslagstekst: v,
+				Caretpos:      len([]rune(v)) + 1,
+				Vejnavn:       v,
+			}
+		}
+		return ret
+	}
+
+	ap := ac.adgangsadresser.search(words, 0)
+	if selected != "" {
+		// The search may return the index postings, so a new slice is used.
+		var sel []int
+		for _, p := range ap {
+			if ac.adgangsadresser.items[p].Vejstykke.Navn == selected {
+				sel = append(sel, p)
+			}
+		}
+		ap = sel
+	}
+
+	var adrp []int
+	switch len(ap) {
+	case 0:
+		adrp = ac.adresser.search(words, limit)
+	case 1:
+		id := ac.adgangsadresser.items[ap[0]].ID
+		for _, p := range ac.adresser.search(words, 0) {
+			if ac.adresser.items[p].Adgangsadresse.ID == id {
+				adrp = append(adrp, p)
+			}
+		}
+		if len(adrp) < 2 {
+			adrp = nil
+		}
+	}
+	if len(adrp) > 0 {
+		adr := ac.adresser.get(limitInts(adrp, limit))
+		ret := make([]AutocompleteResult, len(adr))
+		for i := range adr {
+			a := &adr[i]
+			t := a.Betegnelse()
+			ret[i] = AutocompleteResult{
+				Type:           AutocompleteAdresse,
+				Tekst:          t,
+				Forslagstekst:  t,
+				Caretpos:       len([]rune(t)),
+				Vejnavn:        a.Adgangsadresse.Vejstykke.Navn,
+				AdgangsAdresse: &a.Adgangsadresse,
+				Adresse:        a,
+			}
+		}
+		return ret
+	}
+
+	aa := ac.adgangsadresser.get(limitInts(ap, limit))
+	ret := make([]AutocompleteResult, len(aa))
+	for i := range aa {
+		a := &aa[i]
+		t := a.Betegnelse()
+		ret[i] = AutocompleteResult{
+			Type:           AutocompleteAdgangsadresse,
+			Tekst:          t,
+			Forslagstekst:  t,
+			Caretpos:       len([]rune(t)),
+			Vejnavn:        a.Vejstykke.Navn,
+			AdgangsAdresse: a,
+		}
+	}
+	return ret
+}
+
+// vejnavnSelected returns true if q is the vejnavn followed by whitespace.
+func vejnavnSelected(q, vejnavn string) bool {
+	if len(q) == len(strings.TrimRightFunc(q, unicode.IsSpace)) {
+		return false
+	}
+	return strings.Join(tokenize(q), " ") == strings.Join(tokenize(vejnavn), " ")
+}
+
+// adgangsAdresseLess orders by vejnavn, postnr and husnr.
+func adgangsAdresseLess(a, b *AdgangsAdresse) bool {
+	if a.Vejstykke.Navn != b.Vejstykke.Navn {
+		return a.Vejstykke.Navn < b.Vejstykke.Navn
+	}
+	if a.Postnummer.Nr != b.Postnummer.Nr {
+		return a.Postnummer.Nr < b.Postnummer.Nr
+	}
+	if c := compareNumeric(a.Husnr, b.Husnr); c != 0 {
+		return c < 0
+	}
+	return a.ID < b.ID
+}
+
+// adresseLess orders by adgangsadresse, etage and dør.
+func adresseLess(a, b *Adresse) bool {
+	if a.Adgangsadresse.ID != b.Adgangsadresse.ID {
+		return adgangsAdresseLess(&a.Adgangsadresse, &b.Adgangsadresse)
+	}
+	if ea, eb := etageOrder(a.Etage), etageOrder(b.Etage); ea != eb {
+		return ea < eb
+	}
+	if c := compareNumeric(a.Dør, b.Dør); c != 0 {
+		return c < 0
+	}
+	return a.ID < b.ID
+}
+
+// etageOrder returns the sort order of an etage.
+// Empty is first, followed by kl9 to kl, st and 1 to 99.
+func etageOrder(s string) int {
+	switch {
+	case s == "":
+		return -100
+	case s == "st":
+		return 0
+	case s == "kl":
+		return -1
+	case strings.HasPrefix(s, "kl"):
+		if n, err := strconv.Atoi(s[2:]); err == nil {
+			return -n
+		}
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		return n
+	}
+	return 100
+}
+
+// compareNumeric compares strings with a leading number by the number first,
+// so "2" is before "10" and "10" is before "10A".
+func compareNumeric(a, b string) int {
+	na, ra := leadingNumber(a)
+	nb, rb := leadingNumber(b)
+	switch {
+	case na < nb:
+		return -1
+	case na > nb:
+		return 1
+	}
+	return strings.Compare(ra, rb)
+}
+
+// leadingNumber returns the leading number of s and the rest of the string.
+// If s has no leading number, -1 is returned.
+func leadingNumber(s string) (int, string) {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	n, err := strconv.Atoi(s[:i])
+	if err != nil {
+		return -1, s
+	}
+	return n, s[i:]
+}
+
+// qWord is a word in a search text.
+type qWord struct {
+	text   string
+	prefix bool
+}
+
+// parseQ returns the words of a search text.
+// The last word, and words ending with '*' are matched as prefixes.
+func parseQ(q string) []qWord {
+	var ret []qWord
+	for _, f := range strings.Fields(q) {
+		prefix := strings.HasSuffix(f, "*")
+		tokens := tokenize(f)
+		for i, t := range tokens {
+			ret = append(ret, qWord{text: t, prefix: prefix && i == len(tokens)-1})
+		}
+	}
+	if len(ret) > 0 && len(q) == len(strings.TrimRightFunc(q, unicode.IsSpace)) {
+		ret[len(ret)-1].prefix = true
+	}
+	return ret
+}
+
+// tokenize returns the lower case words in s.
+// Words are separated by anything but letters and digits.
+func tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// textIndex is a word index of entries.
+//
+// The words of all entries are kept in a sorted list,
+// so all words with a given prefix can be found using a binary search.
+type textIndex[T any] struct {
+	items    []T
+	text     func(*T) string
+	less     func(a, b *T) bool
+	words    []string
+	postings [][]int // entries containing each word, in order.
+}
+
+func (t *textIndex[T]) add(items ...T) {
+	t.items = append(t.items, items...)
+}
+
+// build will sort the entries and create the word list.
+func (t *textIndex[T]) build() {
+	sort.SliceStable(t.items, func(i, j int) bool { return t.less(&t.items[i], &t.items[j]) })
+	byWord := make(map[string][]int)
+	for i := range t.items {
+		for _, w := range tokenize(t.text(&t.items[i])) {
+			p := byWord[w]
+			if len(p) > 0 && p[len(p)-1] == i {
+				continue
+			}
+			byWord[w] = append(p, i)
+		}
+	}
+	t.words = make([]string, 0, len(byWord))
+	for w := range byWord {
+		t.words = append(t.words, w)
+	}
+	sort.Strings(t.words)
+	t.postings = make([][]int, len(t.words))
+	for i, w := range t.words {
+		t.postings[i] = byWord[w]
+	}
+}
+
+// lookup returns the entries containing the word.
+func (t *textIndex[T]) lookup(w qWord) []int {
+	i := sort.SearchStrings(t.words, w.text)
+	if !w.prefix {
+		if i < len(t.words) && t.words[i] == w.text {
+			return t.postings[i]
+		}
+		return nil
+	}
+	var ret []int
+	n := 0
+	for j := i; j < len(t.words) && strings.HasPrefix(t.words[j], w.text); j++ {
+		ret = append(ret, t.postings[j]...)
+		n++
+	}
+	if n > 1 {
+		sort.Ints(ret)
+		ret = dedupInts(ret)
+	}
+	return ret
+}
+
+// search returns the positions of entries matching all words, in the order of the entries.
+// If limit > 0, at most limit entries are returned.
+// The returned slice may be shared with the index, and must not be modified.
+func (t *textIndex[T]) search(words []qWord, limit int) []int {
+	if len(words) == 0 {
+		return nil
+	}
+	ret := t.lookup(words[0])
+	for _, w := range words[1:] {
+		if len(ret) == 0 {
+			break
+		}
+		ret = intersectInts(ret, t.lookup(w))
+	}
+	return limitInts(ret, limit)
+}
+
+// limitInts returns the first limit values of v, or all if limit <= 0.
+func limitInts(v []int, limit int) []int {
+	if limit > 0 && len(v) > limit {
+		return v[:limit]
+	}
+	return v
+}
+
+// get returns copies of the entries at the positions.
+func (t *textIndex[T]) get(pos []int) []T {
+	ret := make([]T, len(pos))
+	for i, p := range pos {
+		ret[i] = t.items[p]
+	}
+	return ret
+}
+
+// intersectInts returns the values in both a and b, which must be sorted.
+// The result is a new slice.
+func intersectInts(a, b []int) []int {
+	ret := make([]int, 0, min(len(a), len(b)))
+	for len(a) > 0 && len(b) > 0 {
+		switch {
+		case a[0] < b[0]:
+			a = a[1:]
+		case a[0] > b[0]:
+			b = b[1:]
+		default:
+			ret = append(ret, a[0])
+			a, b = a[1:], b[1:]
+		}
+	}
+	return ret
+}
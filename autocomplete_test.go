@@ -0,0 +1,208 @@
+package dawa
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func testAutocomplete(t *testing.T) *Autocomplete {
+	ac := NewAutocomplete()
+	iter, err := ImportAdresserCSV(bytes.NewBufferString(csv_data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ac.LoadAdresser(iter); err != nil {
+		t.Fatalf("LoadAdresser: %v", err)
+	}
+	piter, err := ImportPostnumreJSON(bytes.NewBufferString(postnumre_json_input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ac.LoadPostnumre(piter); err != nil {
+		t.Fatalf("LoadPostnumre: %v", err)
+	}
+
+	// Several adresser on one adgangsadresse, and a similar vejnavn.
+	aa := AdgangsAdresse{
+		ID:         "aa-1",
+		Husnr:      "12",
+		Vejstykke:  VejstykkeRef{Navn: "Rentemestervej", Kode: "0002"},
+		Postnummer: PostnummerRef{Nr: "2400", Navn: "København NV"},
+	}
+	ac.AddAdresser(
+		Adresse{ID: "a-2", Adgangsadresse: aa, Etage: "1", Dør: "th"},
+		Adresse{ID: "a-1", Adgangsadresse: aa, Etage: "st", Dør: "tv"},
+		Adresse{ID: "a-3", Adgangsadresse: aa, Etage: "1", Dør: "tv"},
+	)
+	ac.AddAdgangsAdresser(
+		AdgangsAdresse{
+			ID:         "aa-2",
+			Husnr:      "2",
+			Vejstykke:  VejstykkeRef{Navn: "Gammel Rentemestervej", Kode: "0003"},
+			Postnummer: PostnummerRef{Nr: "2400", Navn: "København NV"},
+		},
+		// Already added, should be ignored.
+		aa,
+	)
+	return ac
+}
+
+func adresseIDs(a []Adresse) []string {
+	ret := []string{}
+	for _, v := range a {
+		ret = append(ret, v.ID)
+	}
+	return ret
+}
+
+func adgangsAdresseIDs(a []AdgangsAdresse) []string {
+	ret := []string{}
+	for _, v := range a {
+		ret = append(ret, v.ID)
+	}
+	return ret
+}
+
+func TestAutocompleteSearch(t *testing.T) {
+	ac := testAutocomplete(t)
+
+	aa := func(q string, limit int, expect ...string) {
+		t.Helper()
+		got := adgangsAdresseIDs(ac.AdgangsAdresser(q, limit))
+		if expect == nil {
+			expect = []string{}
+		}
+		if !reflect.DeepEqual(got, expect) {
+			t.Errorf("AdgangsAdresser(%q): got %v, expected %v", q, got, expect)
+		}
+	}
+	// Ordered by vejnavn, postnr and husnr.
+	aa("hansensvej", 0, "0a3f508c-3306-32b8-e044-0003ba298018", "0a3f508c-3307-32b8-e044-0003ba298018", "0a3f508c-3309-32b8-e044-0003ba298018")
+	aa("hansensvej", 2, "0a3f508c-3306-32b8-e044-0003ba298018", "0a3f508c-3307-32b8-e044-0003ba298018")
+	// Case insensitive, last word is a prefix.
+	aa("A HANSENSVEJ 5", 0, "0a3f508c-3306-32b8-e044-0003ba298018")
+	// "6" is a prefix of "6792", unless followed by a space.
+	aa("a hansensvej 6", 0, "0a3f508c-3306-32b8-e044-0003ba298018", "0a3f508c-3307-32b8-e044-0003ba298018", "0a3f508c-3309-32b8-e044-0003ba298018")
+	aa("a hansensvej 6 ", 0, "0a3f508c-3307-32b8-e044-0003ba298018")
+	aa("hans", 0, "0a3f508c-3306-32b8-e044-0003ba298018", "0a3f508c-3307-32b8-e044-0003ba298018", "0a3f508c-3309-32b8-e044-0003ba298018")
+	// Other words must match entirely, unless they end with a wildcard.
+	aa("hans vej", 0)
+	aa("hans* 8", 0, "0a3f508c-3309-32b8-e044-0003ba298018")
+	// All words must match.
+	aa("hansensvej 6792 rømø", 0, "0a3f508c-3306-32b8-e044-0003ba298018", "0a3f508c-3307-32b8-e044-0003ba298018", "0a3f508c-3309-32b8-e044-0003ba298018")
+	aa("hansensvej 2400", 0)
+	aa("vråby 5", 0, "0a3f508c-3306-32b8-e044-0003ba298018")
+	// Trailing space, last word must match entirely.
+	aa("hans ", 0)
+	aa("rentemestervej", 0, "aa-2", "aa-1")
+	aa("", 0)
+
+	got := adresseIDs(ac.Adresser("rentemestervej 12", 0))
+	if expect := []string{"a-1", "a-2", "a-3"}; !reflect.DeepEqual(got, expect) {
+		t.Errorf("Adresser: got %v, expected %v", got, expect)
+	}
+	got = adresseIDs(ac.Adresser("rentemestervej 12 1 t", 0))
+	if expect := []string{"a-2", "a-3"}; !reflect.DeepEqual(got, expect) {
+		t.Errorf("Adresser: got %v, expected %v", got, expect)
+	}
+
+	p := ac.Postnumre("99", 0)
+	if len(p) != 3 || p[0].Nr != "9981" {
+		t.Errorf("Postnumre: got %v", p)
+	}
+	p = ac.Postnumre("ålb", 0)
+	if len(p) != 1 || p[0].Nr != "9982" {
+		t.Errorf("Postnumre: got %v", p)
+	}
+
+	v := ac.Vejnavne("rentem", 0)
+	if expect := []string{"Gammel Rentemestervej", "Rentemestervej"}; !reflect.DeepEqual(v, expect) {
+		t.Errorf("Vejnavne: got %v, expected %v", v, expect)
+	}
+}
+
+func TestAutocompleteComplete(t *testing.T) {
+	ac := testAutocomplete(t)
+
+	type result struct {
+		Type, Tekst string
+		Caretpos    int
+	}
+	check := func(q string, limit int, expect ...result) {
+		t.Helper()
+		got := []result{}
+		for _, r := range ac.Complete(q, limit) {
+			got = append(got, result{r.Type, r.Tekst, r.Caretpos})
+			if r.Vejnavn == "" {
+				t.Errorf("Complete(%q): vejnavn not set: %+v", q, r)
+			}
+			if (r.AdgangsAdresse == nil) != (r.Type == AutocompleteVejnavn) || (r.Adresse == nil) != (r.Type != AutocompleteAdresse) {
+				t.Errorf("Complete(%q): unexpected data: %+v", q, r)
+			}
+		}
+		if expect == nil {
+			expect = []result{}
+		}
+		if !reflect.DeepEqual(got, expect) {
+			t.Errorf("Complete(%q):\ngot      %+v\nexpected %+v", q, got, expect)
+		}
+	}
+
+	// Vejnavne are suggested first.
+	check("rentem", 0,
+		result{AutocompleteVejnavn, "Gammel Rentemestervej ", 22},
+		result{AutocompleteVejnavn, "Rentemestervej ", 15},
+	)
+	check("rentem", 1, result{AutocompleteVejnavn, "Gammel Rentemestervej ", 22})
+	check("a hans", 0, result{AutocompleteVejnavn, "A Hansensvej ", 13})
+
+	// When a vejnavn is selected, adgangsadresser are suggested.
+	check("A Hansensvej ", 0,
+		result{AutocompleteAdgangsadresse, "A Hansensvej 5, Vråby, 6792 Rømø", 32},
+		result{AutocompleteAdgangsadresse, "A Hansensvej 6, Vråby, 6792 Rømø", 32},
+		result{AutocompleteAdgangsadresse, "A Hansensvej 8, Vråby, 6792 Rømø", 32},
+	)
+	check("a hansensvej 8", 0, result{AutocompleteAdgangsadresse, "A Hansensvej 8, Vråby, 6792 Rømø", 32})
+
+	// A single adgangsadresse with several adresser.
+	check("Rentemestervej ", 0,
+		result{AutocompleteAdresse, "Rentemestervej 12, st. tv, 2400 København NV", 44},
+		result{AutocompleteAdresse, "Rentemestervej 12, 1. th, 2400 København NV", 43},
+		result{AutocompleteAdresse, "Rentemestervej 12, 1. tv, 2400 København NV", 43},
+	)
+	check("rentemestervej 12, 1. tv", 1, result{AutocompleteAdresse, "Rentemestervej 12, 1. tv, 2400 København NV", 43})
+
+	// Only a single adresse.
+	check("rentemestervej 12 st", 0, result{AutocompleteAdresse, "Rentemestervej 12, st. tv, 2400 København NV", 44})
+
+	check("", 0)
+	check("nowhere", 0)
+}
+
+func TestAutocompleteCompleteLimit(t *testing.T) {
+	ac := NewAutocomplete()
+	const n = 5000
+	items := make([]AdgangsAdresse, n)
+	for i := range items {
+		items[i] = AdgangsAdresse{
+			ID:         fmt.Sprintf("aa-%05d", i),
+			Husnr:      strconv.Itoa(i + 1),
+			Vejstykke:  VejstykkeRef{Navn: "Testvej"},
+			Postnummer: PostnummerRef{Nr: "2400", Navn: "København NV"},
+		}
+	}
+	ac.AddAdgangsAdresser(items...)
+	for i := 0; i < 2; i++ {
+		res := ac.Complete("testvej ", 3)
+		if len(res) != 3 || res[0].AdgangsAdresse.ID != "aa-00000" || res[2].AdgangsAdresse.ID != "aa-00002" {
+			t.Fatalf("Unexpected result: %+v", res)
+		}
+	}
+	// Filtering the selected vejnavn must not change the index.
+	if got := ac.AdgangsAdresser("testvej", 0); len(got) != n {
+		t.Fatalf("Expected %d results, got %d", n, len(got))
+	}
+}
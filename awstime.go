@@ -32,6 +32,28 @@ func ParseTime(s string) (*AwsTime, error) {
 	return &t, nil
 }
 
+// parseFlatTime will parse a time from a flat field, like a CSV column.
+// An empty value returns the zero time.
+func parseFlatTime(s string) (AwsTime, error) {
+	if s == "" {
+		return AwsTime{}, nil
+	}
+	t, err := ParseTime(s)
+	if err != nil {
+		return AwsTime{}, err
+	}
+	return *t, nil
+}
+
+// formatFlatTime will format a time for a flat field, so it can be read by parseFlatTime.
+// The zero time returns an empty value.
+func formatFlatTime(t AwsTime) string {
+	if time.Time(t).IsZero() {
+		return ""
+	}
+	return time.Time(t).In(location).Format("2006-01-02T15:04:05.000")
+}
+
 // MustParseTime will return the time encoding for a single field
 // It the input must be AWS formatted encoding
 func MustParseTime(s string) AwsTime {
@@ -58,8 +80,11 @@ func (t *AwsTime) UnmarshalJSON(b []byte) error {
 
 	// Could not parse, attempt standard unmarshall
 	if err != nil {
+		if unquoted == "null" {
+			return nil
+		}
 		var t2 time.Time
-		err = t2.UnmarshalJSON([]byte(unquoted))
+		err = t2.UnmarshalText([]byte(unquoted))
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,103 @@
+package dawa
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// LabelVejnavnWidth is the maximum length of the vejnavn on a label.
+// Longer vejnavne are replaced by the adresseringsnavn of the vejstykke, if it is set.
+const LabelVejnavnWidth = 20
+
+// Betegnelse returns the official adressebetegnelse of the adresse,
+// for example "Rentemestervej 8, st. tv, 2400 København NV".
+//
+// Etage and dør are normalised, so "stuen" and "TV" becomes "st." and "tv".
+// The supplerende bynavn is included if set.
+func (a Adresse) Betegnelse() string {
+	return betegnelse(&a.Adgangsadresse, a.Adgangsadresse.Vejstykke.Navn, etageDør(a.Etage, a.Dør, false))
+}
+
+// Betegnelse returns the official adressebetegnelse of the adgangsadresse,
+// for example "Rentemestervej 8, 2400 København NV".
+//
+// The supplerende bynavn is included if set.
+func (a AdgangsAdresse) Betegnelse() string {
+	return betegnelse(&a, a.Vejstykke.Navn, "")
+}
+
+// Label returns the adresse as lines for a postal label, for example:
+//		Rentemestervej 8, st. tv.
+//		2400 København NV
+//
+// The supplerende bynavn is on a separate line before the postnummer, if set.
+// If the vejnavn is longer than LabelVejnavnWidth characters, the adresseringsnavn is used.
+// Etage and dør are normalised, with a dot after tv, th and mf.
+func (a Adresse) Label() []string {
+	return label(&a.Adgangsadresse, etageDør(a.Etage, a.Dør, true))
+}
+
+// Label returns the adgangsadresse as lines for a postal label, for example:
+//		Rentemestervej 8
+//		2400 København NV
+//
+// The supplerende bynavn is on a separate line before the postnummer, if set.
+// If the vejnavn is longer than LabelVejnavnWidth characters, the adresseringsnavn is used.
+func (a AdgangsAdresse) Label() []string {
+	return label(&a, "")
+}
+
+// betegnelse returns the adressebetegnelse with the supplied vejnavn and etage/dør.
+func betegnelse(a *AdgangsAdresse, vejnavn, etageDør string) string {
+	parts := make([]string, 0, 4)
+	parts = appendNonEmpty(parts, strings.TrimSpace(vejnavn+" "+a.Husnr))
+	parts = appendNonEmpty(parts, etageDør)
+	parts = appendNonEmpty(parts, a.SupplerendeBynavn)
+	parts = appendNonEmpty(parts, strings.TrimSpace(a.Postnummer.Nr+" "+a.Postnummer.Navn))
+	return strings.Join(parts, ", ")
+}
+
+// label returns the label lines with the supplied etage/dør.
+func label(a *AdgangsAdresse, etageDør string) []string {
+	vejnavn := a.Vejstykke.Navn
+	if utf8.RuneCountInString(vejnavn) > LabelVejnavnWidth && a.Vejstykke.Adresseringsnavn != "" {
+		vejnavn = a.Vejstykke.Adresseringsnavn
+	}
+	first := strings.TrimSpace(vejnavn + " " + a.Husnr)
+	if etageDør != "" {
+		first += ", " + etageDør
+	}
+	lines := make([]string, 0, 3)
+	lines = appendNonEmpty(lines, first)
+	lines = appendNonEmpty(lines, a.SupplerendeBynavn)
+	lines = appendNonEmpty(lines, strings.TrimSpace(a.Postnummer.Nr+" "+a.Postnummer.Navn))
+	return lines
+}
+
+// etageDør returns the normalised etage and dør, for example "st. tv".
+// Etage is followed by a dot. If label is set, tv, th and mf are also followed by a dot.
+func etageDør(e, d string, label bool) string {
+	if e = strings.TrimSpace(e); e != "" {
+		e = strings.ToLower(strings.TrimSuffix(e, "."))
+		if n := etage(e); n != "" {
+			e = n
+		}
+		e += "."
+	}
+	if d = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(d), ".")); d != "" {
+		if n, ok := dørNames[strings.ToLower(d)]; ok {
+			d = n
+			if label {
+				d += "."
+			}
+		}
+	}
+	return strings.TrimSpace(e + " " + d)
+}
+
+func appendNonEmpty(s []string, v string) []string {
+	if v == "" {
+		return s
+	}
+	return append(s, v)
+}
@@ -0,0 +1,79 @@
+package dawa
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestBetegnelse(t *testing.T) {
+	aa := AdgangsAdresse{
+		Husnr:      "8",
+		Vejstykke:  VejstykkeRef{Navn: "Rentemestervej", Adresseringsnavn: "Rentemestervej"},
+		Postnummer: PostnummerRef{Nr: "2400", Navn: "København NV"},
+	}
+	tests := []struct {
+		etage, dør string
+		expect     string
+		label      []string
+	}{
+		{"", "", "Rentemestervej 8, 2400 København NV", []string{"Rentemestervej 8", "2400 København NV"}},
+		{"4", "th", "Rentemestervej 8, 4. th, 2400 København NV", []string{"Rentemestervej 8, 4. th.", "2400 København NV"}},
+		{"st", "tv", "Rentemestervej 8, st. tv, 2400 København NV", []string{"Rentemestervej 8, st. tv.", "2400 København NV"}},
+		{"ST.", "TV.", "Rentemestervej 8, st. tv, 2400 København NV", []string{"Rentemestervej 8, st. tv.", "2400 København NV"}},
+		{"stuen", "", "Rentemestervej 8, st., 2400 København NV", []string{"Rentemestervej 8, st.", "2400 København NV"}},
+		{"kl", "mf", "Rentemestervej 8, kl. mf, 2400 København NV", []string{"Rentemestervej 8, kl. mf.", "2400 København NV"}},
+		{"", "12", "Rentemestervej 8, 12, 2400 København NV", []string{"Rentemestervej 8, 12", "2400 København NV"}},
+		{"2", "1/2", "Rentemestervej 8, 2. 1/2, 2400 København NV", []string{"Rentemestervej 8, 2. 1/2", "2400 København NV"}},
+	}
+	for _, test := range tests {
+		a := Adresse{Adgangsadresse: aa, Etage: test.etage, Dør: test.dør}
+		if got := a.Betegnelse(); got != test.expect {
+			t.Errorf("Betegnelse(%q, %q): got %q, expected %q", test.etage, test.dør, got, test.expect)
+		}
+		if got := a.Label(); !reflect.DeepEqual(got, test.label) {
+			t.Errorf("Label(%q, %q): got %q, expected %q", test.etage, test.dør, got, test.label)
+		}
+	}
+
+	// Supplerende bynavn and a long vejnavn.
+	aa = AdgangsAdresse{
+		Husnr:             "27",
+		SupplerendeBynavn: "Rindby Strand",
+		Vejstykke:         VejstykkeRef{Navn: "Kong Christian den Niendes Vej", Adresseringsnavn: "Kong Chr. IX's Vej"},
+		Postnummer:        PostnummerRef{Nr: "6720", Navn: "Fanø"},
+	}
+	if got, expect := aa.Betegnelse(), "Kong Christian den Niendes Vej 27, Rindby Strand, 6720 Fanø"; got != expect {
+		t.Errorf("Betegnelse: got %q, expected %q", got, expect)
+	}
+	if got, expect := aa.Label(), []string{"Kong Chr. IX's Vej 27", "Rindby Strand", "6720 Fanø"}; !reflect.DeepEqual(got, expect) {
+		t.Errorf("Label: got %q, expected %q", got, expect)
+	}
+	// Without adresseringsnavn the vejnavn is used.
+	aa.Vejstykke.Adresseringsnavn = ""
+	if got, expect := aa.Label()[0], "Kong Christian den Niendes Vej 27"; got != expect {
+		t.Errorf("Label: got %q, expected %q", got, expect)
+	}
+}
+
+func TestBetegnelseJSON(t *testing.T) {
+	// The betegnelse must match the one returned by DAWA.
+	iter, err := ImportAdresserJSON(bytes.NewBufferString(json_input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for {
+		a, err := iter.Next()
+		if err != nil {
+			break
+		}
+		n++
+		if got := a.Betegnelse(); got != a.Adressebetegnelse {
+			t.Errorf("Got %q, expected %q", got, a.Adressebetegnelse)
+		}
+	}
+	if n == 0 {
+		t.Fatal("No adresser")
+	}
+}
@@ -94,3 +94,27 @@ func TestImportSupplBynavnJSON(t *testing.T) {
 		t.Fatalf("ImportSupplBynavnJSON: Expected io.EOF, got:%v", err)
 	}
 }
+
+func TestImportSupplBynavnCSV(t *testing.T) {
+	data := "navn,href\nSønderholm,http://dawa.aws.dk/supplerendebynavne/S%C3%B8nderholm\nVråby,\n"
+	expect := []SupplBynavn{
+		{Navn: "Sønderholm", Href: "http://dawa.aws.dk/supplerendebynavne/S%C3%B8nderholm"},
+		{Navn: "Vråby"},
+	}
+	iter, err := ImportCSV[SupplBynavn](bytes.NewBufferString(data), CSVOptions{})
+	if err != nil {
+		t.Fatalf("ImportSupplBynavnCSV: %v", err)
+	}
+	for i, e := range expect {
+		item, err := iter.Next()
+		if err != nil {
+			t.Fatalf("ImportSupplBynavnCSV, iter.Next(): %v", err)
+		}
+		if !reflect.DeepEqual(*item, e) {
+			t.Fatalf("ImportSupplBynavnCSV %d, value mismatch.\nGot:\n%#v\nExpected:\n%#v\n", i, *item, e)
+		}
+	}
+	if _, err := iter.Next(); err != io.EOF {
+		t.Fatalf("ImportSupplBynavnCSV: Expected io.EOF, got:%v", err)
+	}
+}
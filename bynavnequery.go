@@ -0,0 +1,174 @@
+package dawa
+
+import (
+	"context"
+	"strconv"
+)
+
+// SupplBynavnQuery is a new query for 'supplerende bynavn' objects for searching DAWA.
+// Use NewSupplBynavnQuery() or NewSupplBynavnComplete() to get an initialized object.
+// Example:
+//			// Search for "Sønderholm" in Aalborg
+//			item, err := dawa.NewSupplBynavnQuery().Navn("Sønderholm").Kommunekode("0851").First()
+//
+//			// If err is nil, we go a result
+//			if err == nil {
+//				fmt.Printf("Got item:%+v\n", item)
+//			}
+type SupplBynavnQuery struct {
+	queryGeoJSON
+}
+
+// NewSupplBynavnQuery returns a new query for 'supplerende bynavn' objects for searching DAWA.
+//
+// See documentation at http://dawa.aws.dk/supplerendebynavndok#supplerendebynavnsoegning
+func NewSupplBynavnQuery() *SupplBynavnQuery {
+	return DefaultClient.NewSupplBynavnQuery()
+}
+
+// NewSupplBynavnComplete returns a new autocomplete query for 'supplerende bynavn' objects for searching DAWA.
+//
+// See documentation at http://dawa.aws.dk/supplerendebynavndok#supplerendebynavnautocomplete
+func NewSupplBynavnComplete() *SupplBynavnQuery {
+	return DefaultClient.NewSupplBynavnComplete()
+}
+
+// NewSupplBynavnQuery returns a new query for 'supplerende bynavn' objects for searching DAWA using this client.
+//
+// See documentation at http://dawa.aws.dk/supplerendebynavndok#supplerendebynavnsoegning
+func (c *Client) NewSupplBynavnQuery() *SupplBynavnQuery {
+	return &SupplBynavnQuery{queryGeoJSON: c.newQuery("/supplerendebynavne")}
+}
+
+// NewSupplBynavnComplete returns a new autocomplete query for 'supplerende bynavn' objects for searching DAWA using this client.
+//
+// See documentation at http://dawa.aws.dk/supplerendebynavndok#supplerendebynavnautocomplete
+func (c *Client) NewSupplBynavnComplete() *SupplBynavnQuery {
+	return &SupplBynavnQuery{queryGeoJSON: c.newQuery("/supplerendebynavne/autocomplete")}
+}
+
+// Iter will return an iterator that allows you to read the results
+// one by one.
+//
+// An example:
+//			iter, err := dawa.NewSupplBynavnQuery().Postnr("9000").Iter()
+//			if err != nil {
+// 				panic(err)
+// 			}
+//			defer iter.Close()
+//
+//			for {
+//				a, err := iter.Next()
+//				if err == io.EOF {
+//					break  // we are finished
+//				}
+//				if err != nil {
+//					panic(err)
+//				}
+// 				fmt.Printf("%+v\n", a)
+//			}
+//		}
+func (q SupplBynavnQuery) Iter() (*SupplBynavnIter, error) {
+	return q.IterContext(context.Background())
+}
+
+// IterContext will return an iterator like Iter().
+// If ctx is cancelled, the request is aborted and
+// the iterator will return the context error.
+func (q SupplBynavnQuery) IterContext(ctx context.Context) (*SupplBynavnIter, error) {
+	return iterQuery[SupplBynavn](ctx, q.query)
+}
+
+// All returns all results as an array.
+func (q SupplBynavnQuery) All() ([]SupplBynavn, error) {
+	return q.AllContext(context.Background())
+}
+
+// AllContext returns all results as an array like All().
+// If ctx is cancelled, the request is aborted and the context error is returned.
+func (q SupplBynavnQuery) AllContext(ctx context.Context) ([]SupplBynavn, error) {
+	return allQuery[SupplBynavn](ctx, q.query)
+}
+
+// First will return the first result from a query.
+// Note the entire query is executed, so only use this if you expect a few results.
+//
+// Will return (nil, io.EOF) if there is no results.
+func (q SupplBynavnQuery) First() (*SupplBynavn, error) {
+	return q.FirstContext(context.Background())
+}
+
+// FirstContext will return the first result from a query like First().
+// If ctx is cancelled, the request is aborted and the context error is returned.
+func (q SupplBynavnQuery) FirstContext(ctx context.Context) (*SupplBynavn, error) {
+	return firstQuery[SupplBynavn](ctx, q.query)
+}
+
+// Navn will add a parameter for 'navn' to the SupplBynavnQuery.
+//
+// Navnet på det supplerende bynavn, f.eks. ”Sønderholm”. (Flerværdisøgning mulig).
+//
+// See documentation at http://dawa.aws.dk/supplerendebynavndok#supplerendebynavnsoegning
+func (q *SupplBynavnQuery) Navn(s ...string) *SupplBynavnQuery {
+	q.add(&textQuery{Name: "navn", Values: s, Multi: true, Null: false})
+	return q
+}
+
+// Kommunekode will add a parameter for 'kommunekode' to the SupplBynavnQuery.
+//
+// Kommunekode for det supplerende bynavn. 4 cifre. (Flerværdisøgning mulig).
+//
+// See documentation at http://dawa.aws.dk/supplerendebynavndok#supplerendebynavnsoegning
+func (q *SupplBynavnQuery) Kommunekode(s ...string) *SupplBynavnQuery {
+	q.add(&textQuery{Name: "kommunekode", Values: s, Multi: true, Null: false})
+	return q
+}
+
+// Postnr will add a parameter for 'postnr' to the SupplBynavnQuery.
+//
+// Postnummer. 4 cifre. (Flerværdisøgning mulig).
+//
+// See documentation at http://dawa.aws.dk/supplerendebynavndok#supplerendebynavnsoegning
+func (q *SupplBynavnQuery) Postnr(s ...string) *SupplBynavnQuery {
+	q.add(&textQuery{Name: "postnr", Values: s, Multi: true, Null: false})
+	return q
+}
+
+// Q will add a parameter for 'q' to the SupplBynavnQuery.
+//
+// Søgetekst. Der søges i navnet. Alle ord i søgeteksten skal matche det supplerende bynavn.
+// Wildcard * er tilladt i slutningen af hvert ord.
+//
+// See documentation at http://dawa.aws.dk/supplerendebynavndok#supplerendebynavnsoegning
+func (q *SupplBynavnQuery) Q(s string) *SupplBynavnQuery {
+	q.add(&textQuery{Name: "q", Values: []string{s}, Multi: false, Null: true})
+	return q
+}
+
+// Side will add a parameter for 'side' to the SupplBynavnQuery.
+//
+// Angiver hvilken siden som skal leveres. Se Paginering.
+// http://dawa.aws.dk/generelt#paginering
+//
+// See documentation at http://dawa.aws.dk/supplerendebynavndok#supplerendebynavnsoegning
+func (q *SupplBynavnQuery) Side(i int) *SupplBynavnQuery {
+	q.add(&textQuery{Name: "side", Values: []string{strconv.Itoa(i)}, Multi: false, Null: true})
+	return q
+}
+
+// PerSide will add a parameter for 'per_side' to the SupplBynavnQuery.
+//
+// Antal resultater per side. Se Paginering.
+// http://dawa.aws.dk/generelt#paginering
+//
+// See documentation at http://dawa.aws.dk/supplerendebynavndok#supplerendebynavnsoegning
+func (q *SupplBynavnQuery) PerSide(i int) *SupplBynavnQuery {
+	q.add(&textQuery{Name: "per_side", Values: []string{strconv.Itoa(i)}, Multi: false, Null: true})
+	return q
+}
+
+// NoFormat will disable extra whitespace. Always enabled when querying
+func (q *SupplBynavnQuery) NoFormat() *SupplBynavnQuery {
+	q.add(&textQuery{Name: "noformat", Multi: false, Null: true})
+	return q
+}
@@ -0,0 +1,60 @@
+package dawa
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var SupplBynavnURL = []qb{
+	qb{NewSupplBynavnQuery().URL(), DefaultHost + "/supplerendebynavne"},
+	qb{NewSupplBynavnComplete().URL(), DefaultHost + "/supplerendebynavne/autocomplete"},
+	qb{NewSupplBynavnQuery().Navn(multiParam...).URL(), DefaultHost + "/supplerendebynavne?navn=" + multiEncoded + ""},
+	qb{NewSupplBynavnQuery().Kommunekode(multiParam...).URL(), DefaultHost + "/supplerendebynavne?kommunekode=" + multiEncoded + ""},
+	qb{NewSupplBynavnQuery().Postnr(multiParam...).URL(), DefaultHost + "/supplerendebynavne?postnr=" + multiEncoded + ""},
+	qb{NewSupplBynavnQuery().Q(singleParam).URL(), DefaultHost + "/supplerendebynavne?q=" + singleEncoded + ""},
+	qb{NewSupplBynavnQuery().Side(2).PerSide(10).URL(), DefaultHost + "/supplerendebynavne?side=2&per_side=10"},
+}
+
+func TestSupplBynavnQueryURL(t *testing.T) {
+	for _, q := range SupplBynavnURL {
+		if q.Got != q.Expected {
+			t.Fatalf("Unexpected value of parameter:\n     Was:\t%s\nExpected:\t%s", q.Got, q.Expected)
+		}
+	}
+}
+
+func TestSupplBynavnQuery(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/supplerendebynavne" {
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+		}
+		w.Write([]byte(suppl_bynavn_json_input))
+	}))
+	defer ts.Close()
+
+	c := &Client{BaseURL: ts.URL}
+	iter, err := c.NewSupplBynavnQuery().Kommunekode("0851").Iter()
+	if err != nil {
+		t.Fatalf("Iter: %v", err)
+	}
+	first, err := iter.Next()
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if first.Navn == "" {
+		t.Fatalf("Unexpected result: %+v", first)
+	}
+	// Closing before all entries are read must be safe.
+	if err := iter.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	all, err := c.NewSupplBynavnQuery().All()
+	if err != nil {
+		t.Fatalf("All: %v", err)
+	}
+	if len(all) == 0 || all[0].Navn != first.Navn {
+		t.Fatalf("Unexpected result: %+v", all)
+	}
+}
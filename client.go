@@ -0,0 +1,181 @@
+package dawa
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// Client is used to execute queries against DAWA.
+// It allows you to specify the HTTP client used, the host queries are sent to
+// and headers that should be sent with every request.
+//
+// The zero value is a usable client that uses http.DefaultClient and DefaultHost.
+// The package level query constructors, like NewAdresseQuery(), use DefaultClient.
+//
+// Example:
+//			client := dawa.NewClient(&http.Client{Timeout: 10 * time.Second})
+//			client.UserAgent = "my-application/1.0"
+//			item, err := client.NewAdresseQuery().Vejnavn("Rødkildevej").Husnr("46").First()
+type Client struct {
+	// HTTPClient is used to execute requests.
+	// If nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	// BaseURL is the host queries are sent to, for instance "http://dawa.aws.dk".
+	// If empty, DefaultHost is used.
+	BaseURL string
+
+	// UserAgent will be sent as the User-Agent header if not empty.
+	UserAgent string
+
+	// Header contains additional headers that are sent with every request.
+	Header http.Header
+
+	// Retry controls how requests failing with a transient error are retried.
+	// If nil, requests are not retried.
+	Retry *RetryPolicy
+}
+
+// DefaultClient is the client used by the package level query constructors.
+var DefaultClient = &Client{}
+
+// NewClient returns a new client that will execute requests using the supplied http.Client.
+// If c is nil, http.DefaultClient is used.
+func NewClient(c *http.Client) *Client {
+	return &Client{HTTPClient: c}
+}
+
+// host returns the host queries should be sent to.
+func (c *Client) host() string {
+	if c.BaseURL != "" {
+		return c.BaseURL
+	}
+	return DefaultHost
+}
+
+// httpClient returns the http.Client requests should be executed with.
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// newQuery returns a query for the specified path that will execute using this client.
+func (c *Client) newQuery(path string) queryGeoJSON {
+	return queryGeoJSON{query: query{client: c, host: c.host(), path: path}}
+}
+
+// get will execute a GET request to the specified url, adding configured headers.
+// The request is aborted if ctx is cancelled.
+// If a retry policy is set, requests failing with transient errors are retried.
+func (c *Client) get(ctx context.Context, url string) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := c.do(ctx, url)
+		if c.Retry == nil || attempt >= c.Retry.MaxAttempts {
+			return resp, err
+		}
+		info := RetryInfo{URL: url, Attempt: attempt, Err: err}
+		if err != nil {
+			if !retryableError(ctx, err) {
+				return nil, err
+			}
+			info.Wait = c.Retry.backoff(attempt)
+		} else {
+			if !retryableStatus(resp.StatusCode) {
+				return resp, nil
+			}
+			info.StatusCode = resp.StatusCode
+			info.Wait = c.Retry.backoff(attempt)
+			if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				if c.Retry.MaxRetryAfter > 0 && d > c.Retry.MaxRetryAfter {
+					return resp, nil
+				}
+				info.Wait = d
+			}
+			// Discard the body, so the connection can be reused.
+			io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+		if c.Retry.OnRetry != nil {
+			c.Retry.OnRetry(info)
+		}
+		if err := sleepContext(ctx, info.Wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// do will execute a single GET request to the specified url, adding configured headers.
+func (c *Client) do(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range c.Header {
+		for _, v := range values {
+			req.Header.Add(key, v)
+		}
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	return c.httpClient().Do(req)
+}
+
+// NewAdresseQuery returns a new query for 'adresse objects for searching DAWA using this client.
+//
+// See documentation at http://dawa.aws.dk/adressedok#adressesoegning
+func (c *Client) NewAdresseQuery() *AdresseQuery {
+	return &AdresseQuery{queryGeoJSON: c.newQuery("/adresser")}
+}
+
+// NewAdresseComplete returns a new query for 'adresse' objects for searching DAWA with autocomplete using this client.
+//
+// See documentation at http://dawa.aws.dk/adressedok#adresseautocomplete
+func (c *Client) NewAdresseComplete() *AdresseQuery {
+	return &AdresseQuery{queryGeoJSON: c.newQuery("/adresser/autocomplete")}
+}
+
+// NewAdgangsAdresseQuery returns a new query for 'adgangsadresser objects for searching DAWA using this client.
+//
+// See documentation at http://dawa.aws.dk/adgangsadressedok#adressesoegning
+func (c *Client) NewAdgangsAdresseQuery() *AdgangsAdresseQuery {
+	return &AdgangsAdresseQuery{queryGeoJSON: c.newQuery("/adgangsadresser")}
+}
+
+// NewAdgangsAdresseComplete returns a new query for 'adgangsadresser' objects for searching DAWA with autocomplete using this client.
+//
+// See documentation at http://dawa.aws.dk/adgangsadressedok#adresseautocomplete
+func (c *Client) NewAdgangsAdresseComplete() *AdgangsAdresseQuery {
+	return &AdgangsAdresseQuery{queryGeoJSON: c.newQuery("/adgangsadresser/autocomplete")}
+}
+
+// NewPostnrQuery returns a new query for 'postnummer' objects for searching DAWA using this client.
+//
+// See documentation at http://dawa.aws.dk/postnummerdok#postnummersoegning
+func (c *Client) NewPostnrQuery() *PostnrQuery {
+	return &PostnrQuery{queryGeoJSON: c.newQuery("/postnumre")}
+}
+
+// NewPostnrComplete returns a new autocomplete query for 'postnummer' objects for searching DAWA using this client.
+//
+// See documentation at http://dawa.aws.dk/postnummerdok#postnummersoegning
+func (c *Client) NewPostnrComplete() *PostnrQuery {
+	return &PostnrQuery{queryGeoJSON: c.newQuery("/postnumre/autocomplete")}
+}
+
+// NewListQuery returns query item for searching DAWA for specific list types using this client.
+// See the package level NewListQuery() for supported list types.
+//
+// See documentation at http://dawa.aws.dk/listerdok
+func (c *Client) NewListQuery(listType string, autoComplete bool) *ListQuery {
+	path := "/" + listType
+	if autoComplete {
+		path += "/autocomplete"
+	}
+	return &ListQuery{listType: listType, queryGeoJSON: c.newQuery(path)}
+}
@@ -0,0 +1,138 @@
+package dawa
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClientRequest(t *testing.T) {
+	var got *http.Request
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.Write([]byte(postnumre_json_input))
+	}))
+	defer ts.Close()
+
+	c := NewClient(ts.Client())
+	c.BaseURL = ts.URL
+	c.UserAgent = "dawa-test/1.0"
+	c.Header = http.Header{"X-Test": []string{"value"}}
+
+	q := c.NewPostnrQuery().Nr("9981")
+	if q.URL() != ts.URL+"/postnumre?nr=9981" {
+		t.Fatalf("Unexpected URL: %s", q.URL())
+	}
+	all, err := q.All()
+	if err != nil {
+		t.Fatalf("All: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(all))
+	}
+	if got == nil {
+		t.Fatal("No request received")
+	}
+	if ua := got.Header.Get("User-Agent"); ua != "dawa-test/1.0" {
+		t.Fatalf("Unexpected User-Agent: %q", ua)
+	}
+	if v := got.Header.Get("X-Test"); v != "value" {
+		t.Fatalf("Unexpected X-Test header: %q", v)
+	}
+	if got.URL.Path != "/postnumre" {
+		t.Fatalf("Unexpected path: %s", got.URL.Path)
+	}
+}
+
+func TestClientDefaultHost(t *testing.T) {
+	c := &Client{}
+	if u := c.NewListQuery("regioner", true).URL(); u != DefaultHost+"/regioner/autocomplete" {
+		t.Fatalf("Unexpected URL: %s", u)
+	}
+	if u := NewAdresseQuery().URL(); u != DefaultHost+"/adresser" {
+		t.Fatalf("Unexpected URL: %s", u)
+	}
+}
+
+func TestClientRequestError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"type":"QueryParameterFormatError","title":"Invalid parameter"}`))
+	}))
+	defer ts.Close()
+
+	c := &Client{BaseURL: ts.URL}
+	_, err := c.NewAdresseQuery().Postnr("x").First()
+	rerr, ok := err.(RequestError)
+	if !ok {
+		t.Fatalf("Expected RequestError, got %T: %v", err, err)
+	}
+	if rerr.Type != "QueryParameterFormatError" {
+		t.Fatalf("Unexpected error type: %q", rerr.Type)
+	}
+}
+
+func TestClientIterContext(t *testing.T) {
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Write more items than the iterator buffers, then stall.
+		w.Write([]byte("["))
+		for i := 0; i < 500; i++ {
+			if i > 0 {
+				w.Write([]byte(","))
+			}
+			w.Write([]byte(`{"nr":"9981","navn":"Jerup"}`))
+		}
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	c := &Client{BaseURL: ts.URL}
+	ctx, cancel := context.WithCancel(context.Background())
+	iter, err := c.NewPostnrQuery().IterContext(ctx)
+	if err != nil {
+		t.Fatalf("IterContext: %v", err)
+	}
+	defer iter.Close()
+	if _, err := iter.Next(); err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	cancel()
+	if _, err := iter.Next(); err != context.Canceled {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+
+	// A cancelled context should abort before any request is made.
+	_, err = c.NewPostnrQuery().AllContext(ctx)
+	if err == nil || !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestClientReverseQueryContext(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Stall until the request is aborted.
+		<-r.Context().Done()
+	}))
+	defer ts.Close()
+	c := &Client{BaseURL: ts.URL}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	if _, err := c.NewReverseQueryContext(ctx, "regioner", WGS84Point(12.58, 55.68)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	if _, err := ClientReverseQueryOfContext[Region](ctx, c, WGS84Point(12.58, 55.68)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package dawa
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Magic bytes of the supported compression formats.
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	zipMagic  = []byte{'P', 'K', 0x03, 0x04}
+)
+
+// nopCloser is a closer that does nothing.
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
+
+// decompress will detect gzip, zstd and zip compressed input by the magic bytes,
+// and return a reader of the decompressed content.
+// Other input is returned unchanged.
+//
+// For zip archives the member with the extension ext is read.
+// If the archive has only one file, it is read regardless of the extension.
+// If in is a regular file, the archive is read from it directly, starting at the current offset.
+// Other input, like a network stream, is read into memory, since zip archives must be read from the end.
+//
+// The returned closer must be called when the reader is no longer used.
+func decompress(in io.Reader, ext string) (io.Reader, io.Closer, error) {
+	br := bufio.NewReader(in)
+	magic, err := br.Peek(4)
+	if err != nil && err != io.EOF {
+		return nil, nil, err
+	}
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		r, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, nil, fmt.Errorf("dawa: gzip: %w", err)
+		}
+		return r, r, nil
+	case bytes.HasPrefix(magic, zstdMagic):
+		// A single goroutine decodes synchronously, so the decoder
+		// doesn't leak goroutines if it isn't closed.
+		d, err := zstd.NewReader(br, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, nil, fmt.Errorf("dawa: zstd: %w", err)
+		}
+		rc := d.IOReadCloser()
+		return rc, rc, nil
+	case bytes.HasPrefix(magic, zipMagic):
+		return unzip(in, br, ext)
+	}
+	return br, nopCloser{}, nil
+}
+
+// unzip will open the member with the extension ext of the zip archive in.
+// br must be a buffered reader of in, that hasn't been read from.
+//
+// A regular file is read from the offset where the archive starts,
+// which is the current offset minus what br has buffered.
+// Other input is read into memory.
+func unzip(in io.Reader, br *bufio.Reader, ext string) (io.Reader, io.Closer, error) {
+	var ra io.ReaderAt
+	var size int64
+	if f, ok := in.(interface {
+		io.ReaderAt
+		io.Seeker
+		Stat() (fs.FileInfo, error)
+	}); ok {
+		fi, err := f.Stat()
+		if err == nil && fi.Mode().IsRegular() {
+			var off int64
+			off, err = f.Seek(0, io.SeekCurrent)
+			off -= int64(br.Buffered())
+			if err == nil && off >= 0 && off <= fi.Size() {
+				ra, size = io.NewSectionReader(f, off, fi.Size()-off), fi.Size()-off
+			}
+		}
+	}
+	if ra == nil {
+		b, err := io.ReadAll(br)
+		if err != nil {
+			return nil, nil, err
+		}
+		ra, size = bytes.NewReader(b), int64(len(b))
+	}
+	zr, err := zip.NewReader(ra, size)
+	if err != nil {
+		return nil, nil, fmt.Errorf("dawa: zip: %w", err)
+	}
+	var files, match []*zip.File
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "__MACOSX/") {
+			continue
+		}
+		files = append(files, f)
+		if strings.EqualFold(path.Ext(f.Name), ext) {
+			match = append(match, f)
+		}
+	}
+	if len(match) == 0 && len(files) == 1 {
+		match = files
+	}
+	switch len(match) {
+	case 0:
+		return nil, nil, fmt.Errorf("dawa: zip: no %s file in archive", ext)
+	case 1:
+	default:
+		return nil, nil, fmt.Errorf("dawa: zip: %d %s files in archive", len(match), ext)
+	}
+	rc, err := match[0].Open()
+	if err != nil {
+		return nil, nil, fmt.Errorf("dawa: zip: %w", err)
+	}
+	return rc, rc, nil
+}
@@ -0,0 +1,175 @@
+package dawa
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var compress_csv_input = "nr,navn,stormodtager,href\n9981,Jerup,false,\n9982,Ålbæk,false,\n"
+
+// zstdFrame returns b as a zstd frame with a single raw block.
+func zstdFrame(b []byte) []byte {
+	ret := []byte{0x28, 0xb5, 0x2f, 0xfd, 0x20, byte(len(b))}
+	h := 1 | len(b)<<3
+	ret = append(ret, byte(h), byte(h>>8), byte(h>>16))
+	return append(ret, b...)
+}
+
+func gzipData(b []byte) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write(b)
+	w.Close()
+	return buf.Bytes()
+}
+
+func zipData(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(f, content)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func readPostnumre(t *testing.T, in io.Reader) []string {
+	iter, err := ImportCSV[Postnummer](in, CSVOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for p, err := range iter.All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, p.Nr+" "+p.Navn)
+	}
+	return got
+}
+
+func TestImportCompressedCSV(t *testing.T) {
+	tests := map[string][]byte{
+		"plain":            []byte(compress_csv_input),
+		"gzip":             gzipData([]byte(compress_csv_input)),
+		"zstd":             zstdFrame([]byte(compress_csv_input)),
+		"zip":              zipData(t, map[string]string{"postnumre.csv": compress_csv_input, "README.txt": "x"}),
+		"zip, single file": zipData(t, map[string]string{"postnumre": compress_csv_input}),
+	}
+	for name, data := range tests {
+		got := readPostnumre(t, bytes.NewReader(data))
+		if strings.Join(got, ",") != "9981 Jerup,9982 Ålbæk" {
+			t.Errorf("%s: unexpected result: %v", name, got)
+		}
+	}
+}
+
+func TestImportCompressedFile(t *testing.T) {
+	// Zip archives in files are read without reading them into memory.
+	name := filepath.Join(t.TempDir(), "postnumre.zip")
+	err := os.WriteFile(name, zipData(t, map[string]string{"dir/postnumre.CSV": compress_csv_input}), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	got := readPostnumre(t, f)
+	if strings.Join(got, ",") != "9981 Jerup,9982 Ålbæk" {
+		t.Errorf("unexpected result: %v", got)
+	}
+
+	// The archive is read from the current offset of the file.
+	// The file starts with another archive with the same layout,
+	// which would be read if the offset was ignored.
+	stored := func(content string) []byte {
+		var buf bytes.Buffer
+		w := zip.NewWriter(&buf)
+		f, err := w.CreateHeader(&zip.FileHeader{Name: "postnumre.csv", Method: zip.Store})
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(f, content)
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+	prefix := stored(strings.Replace(compress_csv_input, "Jerup", "Jarup", 1))
+	err = os.WriteFile(name, append(prefix, stored(compress_csv_input)...), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f2, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f2.Close()
+	if _, err := f2.Seek(int64(len(prefix)), io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	got = readPostnumre(t, f2)
+	if strings.Join(got, ",") != "9981 Jerup,9982 Ålbæk" {
+		t.Errorf("offset: unexpected result: %v", got)
+	}
+}
+
+func TestImportCompressedJSON(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"gzip": gzipData([]byte(postnumre_json_input)),
+		"zip":  zipData(t, map[string]string{"postnumre.json": postnumre_json_input, "postnumre.csv": compress_csv_input}),
+	} {
+		iter, err := ImportPostnumreJSON(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		n := 0
+		for p, err := range iter.All() {
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if p.Nr == "" {
+				t.Fatalf("%s: unexpected value: %+v", name, p)
+			}
+			n++
+		}
+		if n != 3 {
+			t.Errorf("%s: expected 3 entries, got %d", name, n)
+		}
+	}
+}
+
+func TestImportCompressedErrors(t *testing.T) {
+	tests := map[string][]byte{
+		"no .csv file":   zipData(t, map[string]string{"a.txt": "x", "b.json": "[]"}),
+		"2 .csv files":   zipData(t, map[string]string{"a.csv": compress_csv_input, "b.csv": compress_csv_input}),
+		"gzip":           gzipData([]byte(compress_csv_input))[:5],
+		"zip":            []byte("PK\x03\x04 truncated"),
+		"unexpected EOF": gzipData([]byte(compress_csv_input))[:20],
+	}
+	for reason, data := range tests {
+		_, err := ImportCSV[Postnummer](bytes.NewReader(data), CSVOptions{})
+		if err == nil || !strings.Contains(err.Error(), reason) {
+			t.Errorf("expected error containing %q, got %v", reason, err)
+		}
+	}
+
+	// Empty input has no header.
+	if _, err := ImportCSV[Postnummer](bytes.NewReader(nil), CSVOptions{}); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
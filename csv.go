@@ -0,0 +1,602 @@
+package dawa
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CSVOptions controls how CSV files are imported.
+type CSVOptions struct {
+	// Lenient will leave values that cannot be parsed, like a non-numeric status, as the zero value.
+	// If false, the import will stop with an error.
+	Lenient bool
+
+	// OnError is called with the error of each row that cannot be imported.
+	// If it returns nil, the row is skipped and the import continues.
+	// Otherwise the import stops with the returned error.
+	// If nil, the import stops with the *ImportError.
+	// Use ImportSummary.Skip to skip rows and get a summary of them.
+	OnError func(err *ImportError) error
+}
+
+// onError returns OnError, or a function that stops the import if it isn't set.
+func (o CSVOptions) onError() func(err *ImportError) error {
+	if o.OnError == nil {
+		return func(err *ImportError) error { return err }
+	}
+	return o.OnError
+}
+
+// ImportCSV will import T from a CSV file, supplied to the reader.
+// An iterator will be returned that return all items.
+//
+// The first line must contain the column names.
+// The columns are mapped by name, so the order doesn't matter and unknown columns are ignored.
+//
+// gzip, zstd and zip compressed files are decompressed automatically.
+// For zip archives the .csv file in the archive is read.
+//
+// Columns are mapped to the fields of T using 'csv' struct tags:
+//
+//			Kode        string        `csv:"kode"`               // The column "kode".
+//			Koordinater []float64     `csv:"længde,bredde"`      // A slice has a column for each element.
+//			Kommune     KommuneRef    `csv:"kommune,href=-"`     // Nested struct with the columns "kommunekode" and "kommunenavn". "href" is left out.
+//			Postnummer  PostnummerRef `csv:",nr=postnr,href=-"`  // Nested struct without prefix, where "nr" is renamed to "postnr".
+//
+// Embedded structs are mapped like nested structs without prefix, unless the tag is "-".
+// Other fields without a tag are not mapped.
+// Supported field types are string, int, float, bool, AwsTime, slices of these, and structs.
+// Empty values are left as the zero value.
+//
+// A row that cannot be imported stops the import with an *ImportError,
+// unless opts.OnError skips it.
+func ImportCSV[T any](in io.Reader, opts CSVOptions) (*Iter[T], error) {
+	m, err := csvMappingOf[T]()
+	if err != nil {
+		return nil, err
+	}
+	return importCSV(in, m, opts)
+}
+
+// ExportCSV will write all items from the iterator to w as a CSV file.
+// The columns are the same as read by ImportCSV, and the first line contains the column names.
+// The iterator is closed when done.
+func ExportCSV[T any](w io.Writer, iter *Iter[T]) error {
+	m, err := csvMappingOf[T]()
+	if err != nil {
+		iter.Close()
+		return err
+	}
+	return exportCSV(w, iter, m)
+}
+
+// csvDoner is implemented by types that are completed when all fields
+// have been set from flat columns, for instance with derived fields.
+type csvDoner interface {
+	csvDone()
+}
+
+// csvField maps one or more flat columns to a field.
+type csvField struct {
+	names []string // Column names. Slices have a column for each element.
+	index []int    // reflect field index.
+	slice bool
+	conv  csvConv // Conversion of the field, or the slice elements.
+}
+
+// csvConv converts a value to and from its flat representation.
+type csvConv struct {
+	set func(v reflect.Value, s string) error
+	get func(v reflect.Value) string
+}
+
+// csvMapping maps the flat DAWA field names, as used in CSV files and GeoJSON properties, to T.
+type csvMapping[T any] struct {
+	fields []csvField
+	done   [][]int // The index of values that implement csvDoner, nested values first.
+}
+
+// csvMappings contains the mappings created by csvMappingOf, by type.
+var csvMappings sync.Map
+
+// csvMappingOf returns the mapping of T, created from the 'csv' struct tags.
+func csvMappingOf[T any]() (*csvMapping[T], error) {
+	t := reflect.TypeFor[T]()
+	if m, ok := csvMappings.Load(t); ok {
+		return m.(*csvMapping[T]), nil
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("dawa: cannot map %v to csv: not a struct", t)
+	}
+	var m csvMapping[T]
+	var err error
+	m.fields, m.done, err = csvStruct(t)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	for _, f := range m.fields {
+		for _, name := range f.names {
+			if seen[name] {
+				return nil, fmt.Errorf("dawa: cannot map %v to csv: duplicate column %q", t, name)
+			}
+			seen[name] = true
+		}
+	}
+	v, _ := csvMappings.LoadOrStore(t, &m)
+	return v.(*csvMapping[T]), nil
+}
+
+// csvStruct returns the fields of the struct type t and the index of the values that implement csvDoner.
+func csvStruct(t reflect.Type) (fields []csvField, done [][]int, err error) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := sf.Tag.Lookup("csv")
+		if tag == "-" || (!ok && !sf.Anonymous) || !sf.IsExported() {
+			continue
+		}
+		if sf.Type.Kind() == reflect.Struct && sf.Type != awsTimeType {
+			nested, nestedDone, err := csvStruct(sf.Type)
+			if err != nil {
+				return nil, nil, err
+			}
+			opts := strings.Split(tag, ",")
+			prefix, rename := opts[0], make(map[string]string)
+			for _, o := range opts[1:] {
+				from, to, ok := strings.Cut(o, "=")
+				if !ok {
+					return nil, nil, fmt.Errorf("dawa: invalid csv tag on %v.%s: %q", t, sf.Name, tag)
+				}
+				rename[from] = to
+			}
+		nestedFields:
+			for _, f := range nested {
+				names := make([]string, len(f.names))
+				for j, name := range f.names {
+					n, ok := rename[name]
+					if !ok {
+						n = prefix + name
+					}
+					if n == "-" {
+						continue nestedFields
+					}
+					names[j] = n
+				}
+				f.names = names
+				f.index = append([]int{i}, f.index...)
+				fields = append(fields, f)
+			}
+			for _, d := range nestedDone {
+				done = append(done, append([]int{i}, d...))
+			}
+			continue
+		}
+		f := csvField{names: strings.Split(tag, ","), index: []int{i}}
+		ft := sf.Type
+		if ft.Kind() == reflect.Slice {
+			f.slice = true
+			ft = ft.Elem()
+		} else if len(f.names) != 1 {
+			return nil, nil, fmt.Errorf("dawa: invalid csv tag on %v.%s: %q", t, sf.Name, tag)
+		}
+		if f.conv, ok = csvConvOf(ft); !ok {
+			return nil, nil, fmt.Errorf("dawa: cannot map %v.%s to csv: unsupported type %v", t, sf.Name, sf.Type)
+		}
+		fields = append(fields, f)
+	}
+	if reflect.PointerTo(t).Implements(csvDonerType) {
+		done = append(done, []int{})
+	}
+	return fields, done, nil
+}
+
+var (
+	awsTimeType  = reflect.TypeFor[AwsTime]()
+	csvDonerType = reflect.TypeFor[csvDoner]()
+)
+
+// csvConvOf returns the conversion of values of type t.
+func csvConvOf(t reflect.Type) (csvConv, bool) {
+	if t == awsTimeType {
+		return csvConv{
+			set: func(v reflect.Value, s string) error {
+				t, err := parseFlatTime(s)
+				v.Set(reflect.ValueOf(t))
+				return err
+			},
+			get: func(v reflect.Value) string {
+				return formatFlatTime(v.Interface().(AwsTime))
+			},
+		}, true
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return csvConv{
+			set: func(v reflect.Value, s string) error {
+				v.SetString(s)
+				return nil
+			},
+			get: reflect.Value.String,
+		}, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return csvConv{
+			set: func(v reflect.Value, s string) error {
+				i, err := strconv.ParseInt(s, 10, t.Bits())
+				v.SetInt(i)
+				return err
+			},
+			get: func(v reflect.Value) string {
+				return strconv.FormatInt(v.Int(), 10)
+			},
+		}, true
+	case reflect.Float32, reflect.Float64:
+		return csvConv{
+			set: func(v reflect.Value, s string) error {
+				f, err := strconv.ParseFloat(s, t.Bits())
+				v.SetFloat(f)
+				return err
+			},
+			get: func(v reflect.Value) string {
+				return strconv.FormatFloat(v.Float(), 'f', -1, t.Bits())
+			},
+		}, true
+	case reflect.Bool:
+		return csvConv{
+			set: func(v reflect.Value, s string) error {
+				b, err := strconv.ParseBool(s)
+				v.SetBool(b)
+				return err
+			},
+			get: func(v reflect.Value) string {
+				return strconv.FormatBool(v.Bool())
+			},
+		}, true
+	}
+	return csvConv{}, false
+}
+
+// set will set the field v from the column values s.
+// Empty values are left as the zero value, and a slice is left nil if all values are empty.
+// If lenient is true, values that cannot be parsed are left as the zero value.
+func (f *csvField) set(v reflect.Value, s []string, lenient bool) error {
+	if !f.slice {
+		if s[0] == "" {
+			return nil
+		}
+		if err := f.conv.set(v, s[0]); err != nil {
+			v.SetZero()
+			if lenient {
+				return nil
+			}
+			return &ImportError{Column: f.names[0], Value: s[0], Err: err}
+		}
+		return nil
+	}
+	empty := true
+	for _, s := range s {
+		empty = empty && s == ""
+	}
+	if empty {
+		return nil
+	}
+	sl := reflect.MakeSlice(v.Type(), len(s), len(s))
+	for i, s := range s {
+		if err := f.conv.set(sl.Index(i), s); err != nil {
+			if lenient {
+				return nil
+			}
+			return &ImportError{Column: f.names[i], Value: s, Err: err}
+		}
+	}
+	v.Set(sl)
+	return nil
+}
+
+// get appends the column values of the field v to s.
+// Zero times and missing slice elements are written as empty values.
+func (f *csvField) get(v reflect.Value, s []string) []string {
+	if !f.slice {
+		return append(s, f.conv.get(v))
+	}
+	for i := range f.names {
+		if i >= v.Len() {
+			s = append(s, "")
+			continue
+		}
+		s = append(s, f.conv.get(v.Index(i)))
+	}
+	return s
+}
+
+// fromFlat returns a T with the values of v, as used by GeoJSON properties.
+// Columns that aren't present in v are empty.
+func (m *csvMapping[T]) fromFlat(v map[string]string, lenient bool) (T, error) {
+	return m.decode(func(f, n int) string {
+		return v[m.fields[f].names[n]]
+	}, lenient)
+}
+
+// columns returns the index of the header column of each field column, or -1 if it isn't present.
+func (m *csvMapping[T]) columns(header []string) [][]int {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[name] = i
+	}
+	cols := make([][]int, len(m.fields))
+	for i, f := range m.fields {
+		cols[i] = make([]int, len(f.names))
+		for j, name := range f.names {
+			c, ok := index[name]
+			if !ok {
+				c = -1
+			}
+			cols[i][j] = c
+		}
+	}
+	return cols
+}
+
+// decode returns a T with the column values returned by value.
+// f is the index of the field, and n the index of the column name of the field.
+// Errors are returned as *ImportError.
+func (m *csvMapping[T]) decode(value func(f, n int) string, lenient bool) (T, error) {
+	var a T
+	rv := reflect.ValueOf(&a).Elem()
+	var v []string
+	for i := range m.fields {
+		f := &m.fields[i]
+		v = v[:0]
+		for j := range f.names {
+			v = append(v, value(i, j))
+		}
+		if err := f.set(rv.FieldByIndex(f.index), v, lenient); err != nil {
+			return a, err
+		}
+	}
+	for _, d := range m.done {
+		rv.FieldByIndex(d).Addr().Interface().(csvDoner).csvDone()
+	}
+	return a, nil
+}
+
+// header returns the column names.
+func (m *csvMapping[T]) header() []string {
+	var ret []string
+	for _, f := range m.fields {
+		ret = append(ret, f.names...)
+	}
+	return ret
+}
+
+// record appends the column values of a to v, in the order of header().
+func (m *csvMapping[T]) record(a *T, v []string) []string {
+	rv := reflect.ValueOf(a).Elem()
+	for i := range m.fields {
+		f := &m.fields[i]
+		v = f.get(rv.FieldByIndex(f.index), v)
+	}
+	return v
+}
+
+// exportCSV will write all items from iter as a CSV file to w.
+// The first line contains the column names.
+// The iterator is closed when done.
+func exportCSV[T any](w io.Writer, iter *Iter[T], m *csvMapping[T]) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(m.header()); err != nil {
+		iter.Close()
+		return err
+	}
+	var rec []string
+	for a, err := range iter.All() {
+		if err != nil {
+			return err
+		}
+		rec = m.record(&a, rec[:0])
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// importCSV will import T from a CSV file, supplied to the reader.
+// The first line must contain the column names.
+// Compressed files are decompressed, see decompress.
+func importCSV[T any](in io.Reader, m *csvMapping[T], opts CSVOptions) (*Iter[T], error) {
+	in, c, err := decompress(in, ".csv")
+	if err != nil {
+		return nil, err
+	}
+	ret := newIter[T](context.Background(), 100)
+	ret.AddCloser(c)
+	r := csv.NewReader(ret.reader(in))
+	r.Comma = ','
+
+	// Read first line as headers
+	name, err := r.Read()
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	if len(name) > 0 {
+		name[0] = strings.TrimPrefix(name[0], "\ufeff")
+	}
+	cols := m.columns(name)
+	onError := opts.onError()
+	go func() {
+		defer close(ret.a)
+		for {
+			records, err := r.Read()
+			if err != nil {
+				// Rows that cannot be parsed, like rows with a wrong number of columns, can be skipped.
+				var perr *csv.ParseError
+				if errors.As(err, &perr) {
+					err = onError(&ImportError{Line: perr.StartLine, Err: perr.Err})
+					if err == nil {
+						continue
+					}
+				}
+				ret.err = ret.closedErr(err)
+				return
+			}
+			a, err := m.decode(func(f, n int) string {
+				if c := cols[f][n]; c >= 0 {
+					return records[c]
+				}
+				return ""
+			}, opts.Lenient)
+			if err != nil {
+				ierr := err.(*ImportError)
+				ierr.Line, _ = r.FieldPos(0)
+				if err = onError(ierr); err == nil {
+					continue
+				}
+				ret.err = ret.closedErr(err)
+				return
+			}
+			if !ret.send(a) {
+				ret.err = ErrIteratorClosed
+				return
+			}
+		}
+	}()
+	return ret, nil
+}
+
+// ImportListCSV will import items of a list type from a CSV file, supplied to the reader.
+//
+// The list types are the same as for NewListQuery.
+// Use the corresponding iterator function, for instance i.NextRegion() to get typed results.
+// The rows are imported like ImportCSV with the supplied options.
+func ImportListCSV(listType string, in io.Reader, opts CSVOptions) (*ListIter, error) {
+	imp, ok := listCSVImporters[listType]
+	if !ok {
+		return nil, fmt.Errorf("Unknown list type: %s", listType)
+	}
+	it, err := imp(in, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &ListIter{it: it, typ: ListQuery{listType: listType}.Type()}, nil
+}
+
+// listCSVImporters contains CSV import functions for all known list types.
+var listCSVImporters = map[string]func(in io.Reader, opts CSVOptions) (anyIter, error){
+	"kommuner":          listCSV[Kommune],
+	"regioner":          listCSV[Region],
+	"sogne":             listCSV[Sogn],
+	"retskredse":        listCSV[Retskreds],
+	"politikredse":      listCSV[Politikreds],
+	"opstillingskredse": listCSV[Opstillingskreds],
+	"valglandsdele":     listCSV[Valglandsdel],
+	"ejerlav":           listCSV[Ejerlav],
+	"adgangsadresser":   listCSV[AdgangsAdresse],
+	"adresser":          listCSV[Adresse],
+	"postnumre":         listCSV[Postnummer],
+}
+
+// listCSV imports a CSV file of T.
+func listCSV[T any](in io.Reader, opts CSVOptions) (anyIter, error) {
+	it, err := ImportCSV[T](in, opts)
+	if err != nil {
+		return nil, err
+	}
+	return it, nil
+}
+
+// ExportListCSV will write all items from a list iterator to w as a CSV file.
+// The columns are the same as read by ImportListCSV.
+// The iterator is closed when done.
+func ExportListCSV(w io.Writer, iter *ListIter) error {
+	switch it := iter.it.(type) {
+	case *Iter[Kommune]:
+		return ExportCSV(w, it)
+	case *Iter[Region]:
+		return ExportCSV(w, it)
+	case *Iter[Sogn]:
+		return ExportCSV(w, it)
+	case *Iter[Retskreds]:
+		return ExportCSV(w, it)
+	case *Iter[Politikreds]:
+		return ExportCSV(w, it)
+	case *Iter[Opstillingskreds]:
+		return ExportCSV(w, it)
+	case *Iter[Valglandsdel]:
+		return ExportCSV(w, it)
+	case *Iter[Ejerlav]:
+		return ExportCSV(w, it)
+	case *Iter[AdgangsAdresse]:
+		return ExportCSV(w, it)
+	case *Iter[Adresse]:
+		return ExportCSV(w, it)
+	case *Iter[Postnummer]:
+		return ExportCSV(w, it)
+	}
+	iter.Close()
+	return fmt.Errorf("dawa: cannot export %T as CSV", iter.typ)
+}
+
+// ExportKommunerCSV will write all "kommuner" from the iterator to w as a CSV file.
+// The columns are the same as read by ImportCSV[Kommune].
+// The iterator is closed when done.
+func ExportKommunerCSV(w io.Writer, iter *Iter[Kommune]) error {
+	return ExportCSV(w, iter)
+}
+
+// ExportRegionerCSV will write all "regioner" from the iterator to w as a CSV file.
+// The columns are the same as read by ImportCSV[Region].
+// The iterator is closed when done.
+func ExportRegionerCSV(w io.Writer, iter *Iter[Region]) error {
+	return ExportCSV(w, iter)
+}
+
+// ExportSogneCSV will write all "sogne" from the iterator to w as a CSV file.
+// The columns are the same as read by ImportCSV[Sogn].
+// The iterator is closed when done.
+func ExportSogneCSV(w io.Writer, iter *Iter[Sogn]) error {
+	return ExportCSV(w, iter)
+}
+
+// ExportRetskredseCSV will write all "retskredse" from the iterator to w as a CSV file.
+// The columns are the same as read by ImportCSV[Retskreds].
+// The iterator is closed when done.
+func ExportRetskredseCSV(w io.Writer, iter *Iter[Retskreds]) error {
+	return ExportCSV(w, iter)
+}
+
+// ExportPolitikredseCSV will write all "politikredse" from the iterator to w as a CSV file.
+// The columns are the same as read by ImportCSV[Politikreds].
+// The iterator is closed when done.
+func ExportPolitikredseCSV(w io.Writer, iter *Iter[Politikreds]) error {
+	return ExportCSV(w, iter)
+}
+
+// ExportOpstillingskredseCSV will write all "opstillingskredse" from the iterator to w as a CSV file.
+// The columns are the same as read by ImportCSV[Opstillingskreds].
+// The iterator is closed when done.
+func ExportOpstillingskredseCSV(w io.Writer, iter *Iter[Opstillingskreds]) error {
+	return ExportCSV(w, iter)
+}
+
+// ExportValglandsdeleCSV will write all "valglandsdele" from the iterator to w as a CSV file.
+// The columns are the same as read by ImportCSV[Valglandsdel].
+// The iterator is closed when done.
+func ExportValglandsdeleCSV(w io.Writer, iter *Iter[Valglandsdel]) error {
+	return ExportCSV(w, iter)
+}
+
+// ExportEjerlavCSV will write all "ejerlav" from the iterator to w as a CSV file.
+// The columns are the same as read by ImportCSV[Ejerlav].
+// The iterator is closed when done.
+func ExportEjerlavCSV(w io.Writer, iter *Iter[Ejerlav]) error {
+	return ExportCSV(w, iter)
+}
@@ -0,0 +1,354 @@
+package dawa
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var kommuner_csv_data = "\ufeff" + `kode,navn,regionskode,regionsnavn,ændret,geo_ændret,geo_version,bbox_xmin
+0101,København,1084,Region Hovedstaden,2018-10-04T20:50:06.493Z,2018-10-04T20:50:06.493Z,3,12.4533
+0147,Frederiksberg,1084,Region Hovedstaden,,,,12.4797
+`
+
+func TestImportKommunerCSV(t *testing.T) {
+	iter, err := ImportCSV[Kommune](strings.NewReader(kommuner_csv_data), CSVOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []Kommune{
+		{KommuneRef: KommuneRef{Kode: "0101", Navn: "København"}, Regionskode: "1084", ChangeInfo: ChangeInfo{Ændret: "2018-10-04T20:50:06.493Z", GeoÆndret: "2018-10-04T20:50:06.493Z", GeoVersion: 3}},
+		{KommuneRef: KommuneRef{Kode: "0147", Navn: "Frederiksberg"}, Regionskode: "1084"},
+	}
+	var got []Kommune
+	for k, err := range iter.All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, k)
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Fatalf("value mismatch.\nGot:\n%#v\nExpected:\n%#v\n", got, expect)
+	}
+}
+
+func TestImportListCSV(t *testing.T) {
+	tests := []struct {
+		listType string
+		data     string
+		expect   interface{}
+	}{
+		{"kommuner", kommuner_csv_data, &Kommune{KommuneRef: KommuneRef{Kode: "0101", Navn: "København"}, Regionskode: "1084", ChangeInfo: ChangeInfo{Ændret: "2018-10-04T20:50:06.493Z", GeoÆndret: "2018-10-04T20:50:06.493Z", GeoVersion: 3}}},
+		{"regioner", "kode,navn,href\n1084,Region Hovedstaden,http://dawa.aws.dk/regioner/1084\n", &Region{RegionRef: RegionRef{Href: "http://dawa.aws.dk/regioner/1084", Kode: "1084", Navn: "Region Hovedstaden"}}},
+		{"sogne", "kode,navn\n9062,Rømø\n", &Sogn{SognRef: SognRef{Kode: "9062", Navn: "Rømø"}}},
+		{"retskredse", "kode,navn\n1147,Retten i Sønderborg\n", &Retskreds{RetskredsRef: RetskredsRef{Kode: "1147", Navn: "Retten i Sønderborg"}}},
+		{"politikredse", "kode,navn,geo_version\n1464,Syd- og Sønderjyllands Politi,2\n", &Politikreds{PolitikredsRef: PolitikredsRef{Kode: "1464", Navn: "Syd- og Sønderjyllands Politi"}, ChangeInfo: ChangeInfo{GeoVersion: 2}}},
+		{"opstillingskredse", "kode,navn\n0051,Tønder\n", &Opstillingskreds{OpstillingskredsRef: OpstillingskredsRef{Kode: "0051", Navn: "Tønder"}}},
+		{"valglandsdele", "bogstav,navn\nC,Syddanmark\n", &Valglandsdel{Bogstav: "C", Navn: "Syddanmark"}},
+		{"ejerlav", "kode,navn\n1470852,\"Kirkeby, Rømø\"\n", &Ejerlav{Kode: 1470852, Navn: "Kirkeby, Rømø"}},
+		{"postnumre", "nr,navn,stormodtager\n6792,Rømø,false\n", &Postnummer{Nr: "6792", Navn: "Rømø"}},
+	}
+	for _, test := range tests {
+		iter, err := ImportListCSV(test.listType, strings.NewReader(test.data), CSVOptions{})
+		if err != nil {
+			t.Fatalf("%s: %v", test.listType, err)
+		}
+		got, err := iter.Next()
+		if err != nil {
+			t.Fatalf("%s: %v", test.listType, err)
+		}
+		if !reflect.DeepEqual(got, test.expect) {
+			t.Errorf("%s: value mismatch.\nGot:\n%#v\nExpected:\n%#v\n", test.listType, got, test.expect)
+		}
+		if reflect.TypeOf(got) != reflect.TypeOf(iter.typ) {
+			t.Errorf("%s: type mismatch: %T, %T", test.listType, got, iter.typ)
+		}
+	}
+
+	// Addresses share the importers.
+	iter, err := ImportListCSV("adresser", strings.NewReader(csv_data), CSVOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := iter.NextAdresse()
+	if err != nil || a.Adressebetegnelse != "A Hansensvej 6, Vråby, 6792 Rømø" {
+		t.Fatalf("Unexpected result: %+v, %v", a, err)
+	}
+
+	if _, err := ImportListCSV("vejnavne", strings.NewReader(kommuner_csv_data), CSVOptions{}); err == nil {
+		t.Fatal("Expected error for unknown list type")
+	}
+
+	// The options are used for the import.
+	iter, err = ImportListCSV("ejerlav", strings.NewReader("kode,navn\nx,a\n2,b\n"), CSVOptions{Lenient: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := iter.NextEjerlav()
+	if err != nil || e.Kode != 0 || e.Navn != "a" {
+		t.Fatalf("Unexpected result: %+v, %v", e, err)
+	}
+}
+
+func TestImportCSVErrors(t *testing.T) {
+	if _, err := ImportCSV[Ejerlav](strings.NewReader(""), CSVOptions{}); err != io.EOF {
+		t.Fatalf("Expected io.EOF, got %v", err)
+	}
+	iter, err := ImportCSV[Ejerlav](strings.NewReader("kode,navn\n1,a\nx,b\n"), CSVOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, err := iter.Next(); err != nil || e.Kode != 1 {
+		t.Fatalf("Unexpected result: %+v, %v", e, err)
+	}
+	if _, err := iter.Next(); err == nil || !strings.Contains(err.Error(), "invalid syntax") {
+		t.Fatalf("Expected parse error, got %v", err)
+	}
+	iter2, err := ImportCSV[Valglandsdel](strings.NewReader("bogstav,navn\nA,a,extra\n"), CSVOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := iter2.Next(); err == nil || err == io.EOF {
+		t.Fatalf("Expected field count error, got %v", err)
+	}
+}
+
+func TestExportCSVRoundTrip(t *testing.T) {
+	all := func(iter *AdresseIter, err error) []Adresse {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ret []Adresse
+		for a, err := range iter.All() {
+			if err != nil {
+				t.Fatal(err)
+			}
+			ret = append(ret, a)
+		}
+		return ret
+	}
+	expect := all(ImportAdresserCSV(strings.NewReader(csv_data)))
+	var buf bytes.Buffer
+	if err := ExportAdresserCSV(&buf, sliceIter(expect...)); err != nil {
+		t.Fatal(err)
+	}
+	// All the columns of the DAWA download must be written.
+	header := strings.Split(strings.SplitN(buf.String(), "\n", 2)[0], ",")
+	for _, col := range strings.Split(strings.SplitN(csv_data, "\n", 2)[0], ",") {
+		if !slices.Contains(header, col) {
+			t.Errorf("Column %q not exported", col)
+		}
+	}
+	got := all(ImportAdresserCSV(&buf))
+	if !reflect.DeepEqual(got, expect) {
+		t.Fatalf("value mismatch.\nGot:\n%#v\nExpected:\n%#v\n", got, expect)
+	}
+
+	// Adgangsadresser
+	aiter, err := ImportAdgangsAdresserCSV(strings.NewReader(adgangs_csv_data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var aexpect []AdgangsAdresse
+	for a, err := range aiter.All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		aexpect = append(aexpect, a)
+	}
+	buf.Reset()
+	if err := ExportAdgangsAdresserCSV(&buf, sliceIter(aexpect...)); err != nil {
+		t.Fatal(err)
+	}
+	aiter, err = ImportAdgangsAdresserCSV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range aexpect {
+		a, err := aiter.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(*a, aexpect[i]) {
+			t.Fatalf("value mismatch.\nGot:\n%#v\nExpected:\n%#v\n", *a, aexpect[i])
+		}
+	}
+}
+
+func TestExportListCSV(t *testing.T) {
+	v := []Vejstykke{{Kode: "9369", Navn: "Vesten Bavnen", Kommune: KommuneRef{Kode: "0563", Navn: "Fanø"}, Historik: Historik{Oprettet: MustParseTime("2010-01-17T11:19:52.237")}}}
+	var buf bytes.Buffer
+	if err := ExportVejstykkerCSV(&buf, sliceIter(v...)); err != nil {
+		t.Fatal(err)
+	}
+	want := "adresseringsnavn,oprettet,ændret,href,kode,kommunekode,kommunenavn,navn\n,2010-01-17T11:19:52.237,,,9369,0563,Fanø,Vesten Bavnen\n"
+	if buf.String() != want {
+		t.Fatalf("Got:\n%s\nExpected:\n%s", buf.String(), want)
+	}
+
+	for _, listType := range []string{"kommuner", "regioner", "politikredse", "valglandsdele", "ejerlav", "postnumre"} {
+		var data string
+		switch listType {
+		case "kommuner":
+			data = kommuner_csv_data
+		case "valglandsdele":
+			data = "bogstav,navn,ændret\nC,\"Syd, Danmark\",2018-10-04T20:50:06.493Z\n"
+		default:
+			data = "kode,nr,navn,geo_version\n1464,6792,Syd- og Sønderjyllands Politi,2.5\n"
+		}
+		iter, err := ImportListCSV(listType, strings.NewReader(data), CSVOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var expect []interface{}
+		for {
+			v, err := iter.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("%s: %v", listType, err)
+			}
+			expect = append(expect, v)
+		}
+		iter, _ = ImportListCSV(listType, strings.NewReader(data), CSVOptions{})
+		buf.Reset()
+		if err := ExportListCSV(&buf, iter); err != nil {
+			t.Fatalf("%s: %v", listType, err)
+		}
+		iter, err = ImportListCSV(listType, &buf, CSVOptions{})
+		if err != nil {
+			t.Fatalf("%s: %v", listType, err)
+		}
+		for i := range expect {
+			v, err := iter.Next()
+			if err != nil {
+				t.Fatalf("%s: %v", listType, err)
+			}
+			if !reflect.DeepEqual(v, expect[i]) {
+				t.Errorf("%s: value mismatch.\nGot:\n%#v\nExpected:\n%#v\n", listType, v, expect[i])
+			}
+		}
+		if _, err := iter.Next(); err != io.EOF {
+			t.Errorf("%s: Expected io.EOF, got %v", listType, err)
+		}
+	}
+}
+
+func TestImportCSVTags(t *testing.T) {
+	type inner struct {
+		Kode string  `csv:"kode"`
+		Navn string  `csv:"navn"`
+		Skip string  `csv:"-"`
+		Tid  AwsTime `csv:"tid"`
+	}
+	type item struct {
+		ChangeInfo
+		Inner   inner     `csv:"inner_,navn=innernavn,tid=-"`
+		Point   []float64 `csv:"x,y"`
+		Ok      bool      `csv:"ok"`
+		N       int64     `csv:"n"`
+		Ignored string
+	}
+	data := "n,ok,x,y,inner_kode,innernavn,geo_version,tid,Ignored\n-5,true,1.5,2,0101,København,3,2000-02-05T18:09:56.000,x\n,,,,,,,,\n"
+	iter, err := ImportCSV[item](strings.NewReader(data), CSVOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []item{
+		{ChangeInfo: ChangeInfo{GeoVersion: 3}, Inner: inner{Kode: "0101", Navn: "København"}, Point: []float64{1.5, 2}, Ok: true, N: -5},
+		{},
+	}
+	for _, e := range expect {
+		got, err := iter.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(*got, e) {
+			t.Fatalf("value mismatch.\nGot:\n%#v\nExpected:\n%#v\n", *got, e)
+		}
+	}
+	if _, err := iter.Next(); err != io.EOF {
+		t.Fatalf("Expected io.EOF, got %v", err)
+	}
+
+	// Export writes the mapped columns in field order.
+	var buf bytes.Buffer
+	if err := ExportCSV(&buf, sliceIter(expect...)); err != nil {
+		t.Fatal(err)
+	}
+	want := "ændret,geo_version,geo_ændret,inner_kode,innernavn,x,y,ok,n\n,3,,0101,København,1.5,2,true,-5\n,0,,,,,,false,0\n"
+	if buf.String() != want {
+		t.Fatalf("Got:\n%s\nExpected:\n%s", buf.String(), want)
+	}
+
+	type badType struct {
+		M map[string]string `csv:"m"`
+	}
+	type badTag struct {
+		Inner inner `csv:"inner,navn"`
+	}
+	type duplicate struct {
+		A string `csv:"a"`
+		B string `csv:"a"`
+	}
+	type twoNames struct {
+		A string `csv:"a,b"`
+	}
+	for _, err := range []error{
+		func() error { _, err := ImportCSV[badType](strings.NewReader(data), CSVOptions{}); return err }(),
+		func() error { _, err := ImportCSV[badTag](strings.NewReader(data), CSVOptions{}); return err }(),
+		func() error { _, err := ImportCSV[duplicate](strings.NewReader(data), CSVOptions{}); return err }(),
+		func() error { _, err := ImportCSV[twoNames](strings.NewReader(data), CSVOptions{}); return err }(),
+		func() error { _, err := ImportCSV[string](strings.NewReader(data), CSVOptions{}); return err }(),
+	} {
+		if err == nil || !strings.HasPrefix(err.Error(), "dawa: ") {
+			t.Errorf("Expected mapping error, got %v", err)
+		}
+	}
+}
+
+func TestImportCSVLenient(t *testing.T) {
+	data := strings.Replace(csv_data, ",1470852,", ",ukendt,", 1)
+	data = strings.Replace(data, "470620,6105713,55.0972751504817,8.53959543878291", "470620,6105713,x,8.53959543878291", 1)
+	iter, err := ImportAdresserCSV(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := iter.Next(); err == nil || !strings.Contains(err.Error(), "column wgs84koordinat_bredde") {
+		t.Fatalf("Expected wgs84koordinat_bredde error, got %v", err)
+	}
+	iter, err = ImportAdresserCSV(strings.NewReader(strings.Replace(csv_data, ",1470852,", ",ukendt,", 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := iter.Next(); err == nil || !strings.Contains(err.Error(), "column ejerlavkode") {
+		t.Fatalf("Expected ejerlavkode error, got %v", err)
+	}
+
+	iter, err = ImportCSV[Adresse](strings.NewReader(data), CSVOptions{Lenient: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := iter.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	aa := a.Adgangsadresse
+	if aa.Ejerlav.Kode != 0 || aa.Ejerlav.Navn != "Kirkeby, Rømø" || a.Status != 1 {
+		t.Fatalf("Unexpected value: %+v", a)
+	}
+	// The WGS84 koordinater could not be parsed, so they are transformed from ETRS89.
+	if len(aa.Adgangspunkt.Koordinater) != 2 || math.Abs(aa.Adgangspunkt.Koordinater[1]-55.0972751504817) > 1e-6 {
+		t.Fatalf("Unexpected koordinater: %v", aa.Adgangspunkt.Koordinater)
+	}
+	// Following rows are not affected.
+	if a, err := iter.Next(); err != nil || a.Adgangsadresse.Ejerlav.Kode != 1470852 {
+		t.Fatalf("Unexpected value: %+v, %v", a, err)
+	}
+}
@@ -0,0 +1,359 @@
+package dawa
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"sync"
+)
+
+// Datavask kategorier.
+//
+// A: Der er fundet en eksakt match, eller der er kun forskel i ubetydelige dele af adressen.
+// B: Der er fundet en match, men med mindre forskelle, som ikke ændrer på hvilken adresse der er tale om.
+// C: Der er fundet en match, men med væsentlige forskelle. Resultatet bør kontrolleres manuelt.
+const (
+	DatavaskKategoriA = "A"
+	DatavaskKategoriB = "B"
+	DatavaskKategoriC = "C"
+)
+
+// DefaultDatavaskConcurrency is the number of concurrent requests used
+// by the batch helpers if no concurrency is specified.
+// Values <= 0 are treated as 1.
+var DefaultDatavaskConcurrency = 4
+
+// DatavaskAdresseResultat is the result of washing an address with a DatavaskAdresseQuery.
+type DatavaskAdresseResultat struct {
+	Kategori   string                 `json:"kategori"`   // Kategorien for matchet. Se DatavaskKategoriA, B og C.
+	Resultater []DatavaskAdresseMatch `json:"resultater"` // Kandidater, sorteret med den bedste match først.
+}
+
+// DatavaskAdgangsAdresseResultat is the result of washing an address with a DatavaskAdgangsAdresseQuery.
+type DatavaskAdgangsAdresseResultat struct {
+	Kategori   string                        `json:"kategori"`   // Kategorien for matchet. Se DatavaskKategoriA, B og C.
+	Resultater []DatavaskAdgangsAdresseMatch `json:"resultater"` // Kandidater, sorteret med den bedste match først.
+}
+
+// DatavaskAdresseMatch is a candidate match for a washed address.
+//
+// DAWA only returns a reduced set of fields for the addresses.
+// The fields returned are filled into Adresse, use GetAdresseID(a.Adresse.ID)
+// to get the complete address.
+type DatavaskAdresseMatch struct {
+	Adresse       Adresse       // Den fundne adresse.
+	AktuelAdresse *Adresse      // Den aktuelle adresse, hvis den fundne adresse er historisk. Ellers nil.
+	Vaskeresultat Vaskeresultat // Detaljer om hvordan adressen matchede.
+}
+
+// DatavaskAdgangsAdresseMatch is a candidate match for a washed address.
+//
+// DAWA only returns a reduced set of fields for the addresses.
+// The fields returned are filled into AdgangsAdresse, use GetAAID(a.AdgangsAdresse.ID)
+// to get the complete address.
+type DatavaskAdgangsAdresseMatch struct {
+	AdgangsAdresse       AdgangsAdresse  // Den fundne adgangsadresse.
+	AktuelAdgangsAdresse *AdgangsAdresse // Den aktuelle adgangsadresse, hvis den fundne adgangsadresse er historisk. Ellers nil.
+	Vaskeresultat        Vaskeresultat   // Detaljer om hvordan adressen matchede.
+}
+
+// Vaskeresultat contains the details of how a washed address matched a candidate.
+type Vaskeresultat struct {
+	Variant                       DatavaskFelter    `json:"variant"`                       // Den variant af adressen, som blev matchet.
+	Afstand                       int               `json:"afstand"`                       // Samlet afstand mellem den søgte og den matchede adresse.
+	Forskelle                     DatavaskForskelle `json:"forskelle"`                     // Afstand for de enkelte felter.
+	ParsetAdresse                 DatavaskFelter    `json:"parsetadresse"`                 // Den søgte adresse, opdelt i felter.
+	UkendteTokens                 []string          `json:"ukendtetokens"`                 // Dele af søgeteksten, som ikke kunne placeres i et felt.
+	AnvendtStormodtagerPostnummer *PostnummerRef    `json:"anvendtstormodtagerpostnummer"` // Hvis der er anvendt et stormodtagerpostnummer, ellers nil.
+}
+
+// DatavaskFelter contains the individual parts of an address.
+type DatavaskFelter struct {
+	Vejnavn           string `json:"vejnavn"`
+	Husnr             string `json:"husnr"`
+	Etage             string `json:"etage"`
+	Dør               string `json:"dør"`
+	SupplerendeBynavn string `json:"supplerendebynavn"`
+	Postnr            string `json:"postnr"`
+	Postnrnavn        string `json:"postnrnavn"`
+}
+
+// DatavaskForskelle contains the distance between the searched and the matched value of each field.
+// 0 means the values are identical.
+type DatavaskForskelle struct {
+	Vejnavn           int `json:"vejnavn"`
+	Husnr             int `json:"husnr"`
+	Etage             int `json:"etage"`
+	Dør               int `json:"dør"`
+	SupplerendeBynavn int `json:"supplerendebynavn"`
+	Postnr            int `json:"postnr"`
+	Postnrnavn        int `json:"postnrnavn"`
+}
+
+// datavaskAdresse is the reduced address format returned by datavask.
+type datavaskAdresse struct {
+	ID                string `json:"id"`
+	Href              string `json:"href"`
+	Status            int    `json:"status"`
+	Vejkode           string `json:"vejkode"`
+	Vejnavn           string `json:"vejnavn"`
+	Husnr             string `json:"husnr"`
+	Etage             string `json:"etage"`
+	Dør               string `json:"dør"`
+	SupplerendeBynavn string `json:"supplerendebynavn"`
+	Postnr            string `json:"postnr"`
+	Postnrnavn        string `json:"postnrnavn"`
+	Kommunekode       string `json:"kommunekode"`
+	AdgangsadresseID  string `json:"adgangsadresseid"`
+}
+
+// fill will fill the fields shared by adresser and adgangsadresser into a.
+func (d datavaskAdresse) fill(a *AdgangsAdresse) {
+	a.Vejstykke.Kode = d.Vejkode
+	a.Vejstykke.Navn = d.Vejnavn
+	a.Husnr = d.Husnr
+	a.SupplerendeBynavn = d.SupplerendeBynavn
+	a.Postnummer.Nr = d.Postnr
+	a.Postnummer.Navn = d.Postnrnavn
+	a.Kommune.Kode = d.Kommunekode
+}
+
+func (d datavaskAdresse) adgangsAdresse() AdgangsAdresse {
+	a := AdgangsAdresse{ID: d.ID, Href: d.Href, Status: d.Status}
+	d.fill(&a)
+	return a
+}
+
+func (d datavaskAdresse) adresse() Adresse {
+	a := Adresse{ID: d.ID, Href: d.Href, Status: d.Status, Etage: d.Etage, Dør: d.Dør}
+	a.Adgangsadresse.ID = d.AdgangsadresseID
+	d.fill(&a.Adgangsadresse)
+	return a
+}
+
+// datavaskMatch is the JSON format of a single match.
+type datavaskMatch struct {
+	Adresse       datavaskAdresse  `json:"adresse"`
+	AktuelAdresse *datavaskAdresse `json:"aktueladresse"`
+	Vaskeresultat Vaskeresultat    `json:"vaskeresultat"`
+}
+
+// UnmarshalJSON converts the reduced address format to an Adresse.
+func (m *DatavaskAdresseMatch) UnmarshalJSON(b []byte) error {
+	var d datavaskMatch
+	if err := json.Unmarshal(b, &d); err != nil {
+		return err
+	}
+	m.Adresse = d.Adresse.adresse()
+	m.AktuelAdresse = nil
+	if d.AktuelAdresse != nil {
+		a := d.AktuelAdresse.adresse()
+		m.AktuelAdresse = &a
+	}
+	m.Vaskeresultat = d.Vaskeresultat
+	return nil
+}
+
+// UnmarshalJSON converts the reduced address format to an AdgangsAdresse.
+func (m *DatavaskAdgangsAdresseMatch) UnmarshalJSON(b []byte) error {
+	var d datavaskMatch
+	if err := json.Unmarshal(b, &d); err != nil {
+		return err
+	}
+	m.AdgangsAdresse = d.Adresse.adgangsAdresse()
+	m.AktuelAdgangsAdresse = nil
+	if d.AktuelAdresse != nil {
+		a := d.AktuelAdresse.adgangsAdresse()
+		m.AktuelAdgangsAdresse = &a
+	}
+	m.Vaskeresultat = d.Vaskeresultat
+	return nil
+}
+
+// DatavaskAdresseQuery is a query for washing a free-text address against 'adresser'.
+// Use NewDatavaskAdresseQuery() to get an initialized object.
+// Example:
+//			res, err := dawa.NewDatavaskAdresseQuery("Rante mester vej 8, 4, 2400 København NV").Result()
+//			if err == nil && res.Kategori != dawa.DatavaskKategoriC {
+//				fmt.Printf("Got address:%s\n", res.Resultater[0].Adresse.ID)
+//			}
+type DatavaskAdresseQuery struct {
+	query
+}
+
+// DatavaskAdgangsAdresseQuery is a query for washing a free-text address against 'adgangsadresser'.
+// Use NewDatavaskAdgangsAdresseQuery() to get an initialized object.
+type DatavaskAdgangsAdresseQuery struct {
+	query
+}
+
+// NewDatavaskAdresseQuery returns a new query for washing the supplied address against 'adresser'.
+//
+// See documentation at http://dawa.aws.dk/dok/api/adresse#datavask
+func NewDatavaskAdresseQuery(betegnelse string) *DatavaskAdresseQuery {
+	return DefaultClient.NewDatavaskAdresseQuery(betegnelse)
+}
+
+// NewDatavaskAdgangsAdresseQuery returns a new query for washing the supplied address against 'adgangsadresser'.
+//
+// See documentation at http://dawa.aws.dk/dok/api/adgangsadresse#datavask
+func NewDatavaskAdgangsAdresseQuery(betegnelse string) *DatavaskAdgangsAdresseQuery {
+	return DefaultClient.NewDatavaskAdgangsAdresseQuery(betegnelse)
+}
+
+// NewDatavaskAdresseQuery returns a new query for washing the supplied address against 'adresser' using this client.
+//
+// See documentation at http://dawa.aws.dk/dok/api/adresse#datavask
+func (c *Client) NewDatavaskAdresseQuery(betegnelse string) *DatavaskAdresseQuery {
+	q := &DatavaskAdresseQuery{query: c.newQuery("/datavask/adresser").query}
+	return q.Betegnelse(betegnelse)
+}
+
+// NewDatavaskAdgangsAdresseQuery returns a new query for washing the supplied address against 'adgangsadresser' using this client.
+//
+// See documentation at http://dawa.aws.dk/dok/api/adgangsadresse#datavask
+func (c *Client) NewDatavaskAdgangsAdresseQuery(betegnelse string) *DatavaskAdgangsAdresseQuery {
+	q := &DatavaskAdgangsAdresseQuery{query: c.newQuery("/datavask/adgangsadresser").query}
+	return q.Betegnelse(betegnelse)
+}
+
+// Betegnelse will set the parameter for 'betegnelse' on the DatavaskAdresseQuery.
+//
+// Adressebetegnelsen for den adresse, der ønskes vasket, f.eks. "Rante mester vej 8, 4. th, 2400 København NV".
+func (q *DatavaskAdresseQuery) Betegnelse(s string) *DatavaskAdresseQuery {
+	q.set(&textQuery{Name: "betegnelse", Values: []string{s}, Multi: false, Null: false})
+	return q
+}
+
+// Betegnelse will set the parameter for 'betegnelse' on the DatavaskAdgangsAdresseQuery.
+//
+// Adressebetegnelsen for den adresse, der ønskes vasket, f.eks. "Rante mester vej 8, 2400 København NV".
+func (q *DatavaskAdgangsAdresseQuery) Betegnelse(s string) *DatavaskAdgangsAdresseQuery {
+	q.set(&textQuery{Name: "betegnelse", Values: []string{s}, Multi: false, Null: false})
+	return q
+}
+
+// Result will execute the query and return the result.
+func (q DatavaskAdresseQuery) Result() (*DatavaskAdresseResultat, error) {
+	return q.ResultContext(context.Background())
+}
+
+// ResultContext will execute the query like Result().
+// If ctx is cancelled, the request is aborted and the context error is returned.
+func (q DatavaskAdresseQuery) ResultContext(ctx context.Context) (*DatavaskAdresseResultat, error) {
+	return datavaskQuery[DatavaskAdresseResultat](ctx, q.query)
+}
+
+// Result will execute the query and return the result.
+func (q DatavaskAdgangsAdresseQuery) Result() (*DatavaskAdgangsAdresseResultat, error) {
+	return q.ResultContext(context.Background())
+}
+
+// ResultContext will execute the query like Result().
+// If ctx is cancelled, the request is aborted and the context error is returned.
+func (q DatavaskAdgangsAdresseQuery) ResultContext(ctx context.Context) (*DatavaskAdgangsAdresseResultat, error) {
+	return datavaskQuery[DatavaskAdgangsAdresseResultat](ctx, q.query)
+}
+
+// datavaskQuery will execute the query and decode the result as R.
+func datavaskQuery[R any](ctx context.Context, q query) (*R, error) {
+	q = q.clone()
+	q.set(&textQuery{Name: "noformat", Null: true})
+	resp, err := q.RequestContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Close()
+	b, err := ioutil.ReadAll(resp)
+	if err != nil {
+		return nil, err
+	}
+	var ret R
+	err = json.Unmarshal(b, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// DatavaskBatchResult is the result of washing a single address in a batch.
+type DatavaskBatchResult[R any] struct {
+	Betegnelse string // The address that was washed.
+	Result     *R     // The result. Nil if Err is set.
+	Err        error  // Error returned when washing the address.
+}
+
+// DatavaskAdresser will wash all the supplied addresses against 'adresser'.
+// See (*Client).DatavaskAdresser for details.
+func DatavaskAdresser(ctx context.Context, betegnelser []string, concurrency int) []DatavaskBatchResult[DatavaskAdresseResultat] {
+	return DefaultClient.DatavaskAdresser(ctx, betegnelser, concurrency)
+}
+
+// DatavaskAdgangsAdresser will wash all the supplied addresses against 'adgangsadresser'.
+// See (*Client).DatavaskAdgangsAdresser for details.
+func DatavaskAdgangsAdresser(ctx context.Context, betegnelser []string, concurrency int) []DatavaskBatchResult[DatavaskAdgangsAdresseResultat] {
+	return DefaultClient.DatavaskAdgangsAdresser(ctx, betegnelser, concurrency)
+}
+
+// DatavaskAdresser will wash all the supplied addresses against 'adresser'.
+//
+// No more than 'concurrency' requests will be running at the same time.
+// If concurrency is <= 0, DefaultDatavaskConcurrency is used.
+//
+// The results are returned in the same order as the input.
+// Errors are reported for each address, so a failing address
+// does not prevent the remaining from being washed.
+// If ctx is cancelled, addresses not yet washed will have the context error set.
+func (c *Client) DatavaskAdresser(ctx context.Context, betegnelser []string, concurrency int) []DatavaskBatchResult[DatavaskAdresseResultat] {
+	return datavaskBatch(ctx, betegnelser, concurrency, func(ctx context.Context, s string) (*DatavaskAdresseResultat, error) {
+		return c.NewDatavaskAdresseQuery(s).ResultContext(ctx)
+	})
+}
+
+// DatavaskAdgangsAdresser will wash all the supplied addresses against 'adgangsadresser'.
+//
+// No more than 'concurrency' requests will be running at the same time.
+// If concurrency is <= 0, DefaultDatavaskConcurrency is used.
+//
+// The results are returned in the same order as the input.
+// Errors are reported for each address, so a failing address
+// does not prevent the remaining from being washed.
+// If ctx is cancelled, addresses not yet washed will have the context error set.
+func (c *Client) DatavaskAdgangsAdresser(ctx context.Context, betegnelser []string, concurrency int) []DatavaskBatchResult[DatavaskAdgangsAdresseResultat] {
+	return datavaskBatch(ctx, betegnelser, concurrency, func(ctx context.Context, s string) (*DatavaskAdgangsAdresseResultat, error) {
+		return c.NewDatavaskAdgangsAdresseQuery(s).ResultContext(ctx)
+	})
+}
+
+// datavaskBatch will call wash for each input with at most concurrency calls running.
+func datavaskBatch[R any](ctx context.Context, in []string, concurrency int, wash func(context.Context, string) (*R, error)) []DatavaskBatchResult[R] {
+	if concurrency <= 0 {
+		concurrency = DefaultDatavaskConcurrency
+	}
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	ret := make([]DatavaskBatchResult[R], len(in))
+	idx := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency && i < len(in); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range idx {
+				ret[j].Result, ret[j].Err = wash(ctx, in[j])
+			}
+		}()
+	}
+	for i, s := range in {
+		ret[i].Betegnelse = s
+		if err := ctx.Err(); err != nil {
+			ret[i].Err = err
+			continue
+		}
+		idx <- i
+	}
+	close(idx)
+	wg.Wait()
+	return ret
+}
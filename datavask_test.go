@@ -0,0 +1,186 @@
+package dawa
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var datavask_json_input = `{
+  "kategori": "B",
+  "resultater": [
+    {
+      "adresse": {
+        "id": "0a3f50a0-73bf-32b8-e044-0003ba298018",
+        "vejnavn": "Rentemestervej",
+        "adresseringsvejnavn": "Rentemestervej",
+        "husnr": "8",
+        "etage": "4",
+        "dør": null,
+        "supplerendebynavn": null,
+        "postnr": "2400",
+        "postnrnavn": "København NV",
+        "status": 1,
+        "href": "http://dawa.aws.dk/adresser/0a3f50a0-73bf-32b8-e044-0003ba298018",
+        "adgangsadresseid": "0a3f507a-e179-32b8-e044-0003ba298018"
+      },
+      "aktueladresse": null,
+      "vaskeresultat": {
+        "variant": {
+          "vejnavn": "Rentemestervej",
+          "husnr": "8",
+          "etage": "4",
+          "dør": null,
+          "supplerendebynavn": null,
+          "postnr": "2400",
+          "postnrnavn": "København NV"
+        },
+        "afstand": 2,
+        "forskelle": {
+          "vejnavn": 2,
+          "husnr": 0,
+          "etage": 0,
+          "dør": 0,
+          "supplerendebynavn": 0,
+          "postnr": 0,
+          "postnrnavn": 0
+        },
+        "parsetadresse": {
+          "vejnavn": "Rante mester vej",
+          "husnr": "8",
+          "etage": "4",
+          "postnr": "2400",
+          "postnrnavn": "København NV"
+        },
+        "ukendtetokens": [],
+        "anvendtstormodtagerpostnummer": null
+      }
+    }
+  ]
+}`
+
+func TestDatavaskQueryURL(t *testing.T) {
+	tests := []qb{
+		{NewDatavaskAdresseQuery(singleParam).URL(), DefaultHost + "/datavask/adresser?betegnelse=" + singleEncoded},
+		{NewDatavaskAdgangsAdresseQuery(singleParam).URL(), DefaultHost + "/datavask/adgangsadresser?betegnelse=" + singleEncoded},
+		{NewDatavaskAdresseQuery("a").Betegnelse("b").URL(), DefaultHost + "/datavask/adresser?betegnelse=b"},
+	}
+	for _, q := range tests {
+		if q.Got != q.Expected {
+			t.Fatalf("Unexpected URL:\n     Was:\t%s\nExpected:\t%s", q.Got, q.Expected)
+		}
+	}
+}
+
+func TestDatavask(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("betegnelse") != "Rante mester vej 8, 4, 2400 København NV" {
+			t.Errorf("Unexpected betegnelse: %q", r.URL.Query().Get("betegnelse"))
+		}
+		w.Write([]byte(datavask_json_input))
+	}))
+	defer ts.Close()
+	c := &Client{BaseURL: ts.URL}
+
+	res, err := c.NewDatavaskAdresseQuery("Rante mester vej 8, 4, 2400 København NV").Result()
+	if err != nil {
+		t.Fatalf("Result: %v", err)
+	}
+	if res.Kategori != DatavaskKategoriB || len(res.Resultater) != 1 {
+		t.Fatalf("Unexpected result: %+v", res)
+	}
+	m := res.Resultater[0]
+	if m.Adresse.ID != "0a3f50a0-73bf-32b8-e044-0003ba298018" || m.Adresse.Etage != "4" {
+		t.Fatalf("Unexpected adresse: %+v", m.Adresse)
+	}
+	if m.Adresse.Adgangsadresse.ID != "0a3f507a-e179-32b8-e044-0003ba298018" || m.Adresse.Adgangsadresse.Vejstykke.Navn != "Rentemestervej" {
+		t.Fatalf("Unexpected adgangsadresse: %+v", m.Adresse.Adgangsadresse)
+	}
+	if m.Adresse.Adgangsadresse.Postnummer.Nr != "2400" || m.AktuelAdresse != nil {
+		t.Fatalf("Unexpected match: %+v", m)
+	}
+	if m.Vaskeresultat.Forskelle.Vejnavn != 2 || m.Vaskeresultat.ParsetAdresse.Vejnavn != "Rante mester vej" {
+		t.Fatalf("Unexpected vaskeresultat: %+v", m.Vaskeresultat)
+	}
+
+	ares, err := c.NewDatavaskAdgangsAdresseQuery("Rante mester vej 8, 4, 2400 København NV").Result()
+	if err != nil {
+		t.Fatalf("Result: %v", err)
+	}
+	if a := ares.Resultater[0].AdgangsAdresse; a.ID != "0a3f50a0-73bf-32b8-e044-0003ba298018" || a.Husnr != "8" {
+		t.Fatalf("Unexpected adgangsadresse: %+v", a)
+	}
+}
+
+func TestDatavaskBatch(t *testing.T) {
+	var running, max int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		if strings.HasPrefix(r.URL.Query().Get("betegnelse"), "fejl") {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"type":"QueryParameterFormatError","title":"Invalid parameter"}`))
+			return
+		}
+		w.Write([]byte(datavask_json_input))
+	}))
+	defer ts.Close()
+	c := &Client{BaseURL: ts.URL}
+
+	in := []string{"a", "b", "fejl", "c", "d", "e", "f", "g"}
+	res := c.DatavaskAdresser(context.Background(), in, 3)
+	if len(res) != len(in) {
+		t.Fatalf("Expected %d results, got %d", len(in), len(res))
+	}
+	for i, r := range res {
+		if r.Betegnelse != in[i] {
+			t.Fatalf("Result %d: expected betegnelse %q, got %q", i, in[i], r.Betegnelse)
+		}
+		if in[i] == "fejl" {
+			if _, ok := r.Err.(RequestError); !ok || r.Result != nil {
+				t.Fatalf("Expected RequestError, got %v", r.Err)
+			}
+			continue
+		}
+		if r.Err != nil || r.Result.Kategori != DatavaskKategoriB {
+			t.Fatalf("Result %d: %+v, %v", i, r.Result, r.Err)
+		}
+	}
+	if m := atomic.LoadInt32(&max); m > 3 {
+		t.Fatalf("Expected at most 3 concurrent requests, got %d", m)
+	}
+
+	// A default of 0 runs one request at a time.
+	defer func(n int) { DefaultDatavaskConcurrency = n }(DefaultDatavaskConcurrency)
+	DefaultDatavaskConcurrency = 0
+	atomic.StoreInt32(&max, 0)
+	done := make(chan []DatavaskBatchResult[DatavaskAdresseResultat])
+	go func() { done <- c.DatavaskAdresser(context.Background(), in[:3], 0) }()
+	select {
+	case res = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("DatavaskAdresser did not return")
+	}
+	if res[0].Err != nil || res[2].Err == nil || atomic.LoadInt32(&max) != 1 {
+		t.Fatalf("Unexpected result: %+v, %d concurrent", res, atomic.LoadInt32(&max))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, r := range c.DatavaskAdgangsAdresser(ctx, in, 0) {
+		if r.Err != context.Canceled {
+			t.Fatalf("Expected context.Canceled, got %v", r.Err)
+		}
+	}
+}
@@ -15,29 +15,94 @@
 package dawa
 
 import (
+	"errors"
 	"io"
+	"sync"
 )
 
 // modify JSONStrictFieldCheck to return an error on unknown fields on JSON import.
 // If true, return an error if a map in the stream has a key which does not map to any field; else read and discard the key and value in the stream and proceed to the next.
 var JSONStrictFieldCheck = false
 
+// ErrIteratorClosed is returned by an iterator when Close has been called.
+var ErrIteratorClosed = errors.New("dawa: iterator closed")
+
+// closer handles closing of an iterator.
+// When closed, the producer is signalled to stop, attached readers
+// are closed and the channel is drained so the producer can exit.
 type closer struct {
-	c []io.Closer
+	c     []io.Closer
+	done  chan struct{} // Closed when the iterator is closed.
+	once  sync.Once
+	drain func() // Receives from the iterator channel until it is closed.
+}
+
+// init will set up the closer. drain must read values until
+// the channel of the iterator is closed.
+func (c *closer) init(drain func()) {
+	c.done = make(chan struct{})
+	c.drain = drain
+}
+
+// stop will signal the producer to stop.
+func (c *closer) stop() {
+	c.once.Do(func() {
+		if c.done != nil {
+			close(c.done)
+		}
+	})
+}
+
+// closedErr will return ErrIteratorClosed if the iterator has been closed, otherwise err.
+func (c *closer) closedErr(err error) error {
+	select {
+	case <-c.done:
+		return ErrIteratorClosed
+	default:
+	}
+	return err
 }
 
-// Call this when you are finished using the object
+// reader will return a reader that returns ErrIteratorClosed
+// when the iterator has been closed.
+func (c *closer) reader(r io.Reader) io.Reader {
+	return &stopReader{r: r, done: c.done}
+}
+
+// Call this when you are finished using the object.
+// The goroutine producing values will be stopped
+// and any attached readers will be closed.
 func (c *closer) Close() error {
+	c.stop()
+	var err error
 	for _, cl := range c.c {
-		err := cl.Close()
-		if err != nil {
-			return err
+		e := cl.Close()
+		if e != nil && err == nil {
+			err = e
 		}
 	}
 	c.c = nil
-	return nil
+	if c.drain != nil {
+		c.drain()
+	}
+	return err
 }
 
 func (c *closer) AddCloser(a io.Closer) {
 	c.c = append(c.c, a)
 }
+
+// stopReader will return ErrIteratorClosed when done is closed.
+type stopReader struct {
+	r    io.Reader
+	done chan struct{}
+}
+
+func (s *stopReader) Read(p []byte) (int, error) {
+	select {
+	case <-s.done:
+		return 0, ErrIteratorClosed
+	default:
+	}
+	return s.r.Read(p)
+}
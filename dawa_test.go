@@ -0,0 +1,109 @@
+package dawa
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// repeatJSON will create a JSON array with the single object repeated n times.
+func repeatJSON(obj string, n int) string {
+	items := make([]string, n)
+	for i := range items {
+		items[i] = obj
+	}
+	return "[" + strings.Join(items, ",") + "]"
+}
+
+// repeatCSV will create a CSV file with the data rows repeated n times.
+func repeatCSV(data string, n int) string {
+	lines := strings.SplitN(data, "\n", 2)
+	return lines[0] + "\n" + strings.Repeat(lines[1], n)
+}
+
+// waitGoroutines will wait until the number of goroutines is at most n.
+func waitGoroutines(t *testing.T, n int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > n {
+		if time.Now().After(deadline) {
+			buf := make([]byte, 1<<16)
+			buf = buf[:runtime.Stack(buf, true)]
+			t.Fatalf("Expected at most %d goroutines, got %d:\n%s", n, runtime.NumGoroutine(), buf)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestIterCloseNoLeak(t *testing.T) {
+	const n = 1000
+	postnr := repeatJSON(`{"nr":"9981","navn":"Jerup"}`, n)
+	before := runtime.NumGoroutine()
+
+	type iter interface {
+		Close() error
+	}
+	var iters []iter
+
+	a, _ := ImportAdresserCSV(bytes.NewBufferString(repeatCSV(csv_data, n)))
+	a.Next()
+	iters = append(iters, a)
+
+	aa, _ := ImportAdgangsAdresserCSV(bytes.NewBufferString(repeatCSV(adgangs_csv_data, n)))
+	aa.Next()
+	iters = append(iters, aa)
+
+	aj, _ := ImportAdresserJSON(strings.NewReader(repeatJSON(`{"id":"0a3f50a0-73ca-32b8-e044-0003ba298018"}`, n)))
+	aj.Next()
+	iters = append(iters, aj)
+
+	aaj, _ := ImportAdgangsAdresserJSON(strings.NewReader(repeatJSON(`{"husnr":"14"}`, n)))
+	aaj.Next()
+	iters = append(iters, aaj)
+
+	p, _ := ImportPostnumreJSON(strings.NewReader(postnr))
+	p.Next()
+	iters = append(iters, p)
+
+	v, _ := ImportVejstykkerJSON(strings.NewReader(repeatJSON(`{"kode":"0004"}`, n)))
+	v.Next()
+	iters = append(iters, v)
+
+	s, _ := ImportSupplBynavnJSON(strings.NewReader(repeatJSON(`{"navn":"Sønderholm"}`, n)))
+	s.Next()
+	iters = append(iters, s)
+
+	for _, it := range iters {
+		if err := it.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+	}
+	waitGoroutines(t, before)
+
+	// Next after Close should not block.
+	if _, err := p.Next(); err != ErrIteratorClosed {
+		t.Fatalf("Expected ErrIteratorClosed, got %v", err)
+	}
+
+	// First should not leave the decoder running.
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(postnr))
+	}))
+	c := &Client{BaseURL: ts.URL, HTTPClient: ts.Client()}
+	for i := 0; i < 10; i++ {
+		if _, err := c.NewPostnrQuery().First(); err != nil {
+			t.Fatalf("First: %v", err)
+		}
+		it, err := c.NewListQuery("postnumre", false).Iter()
+		if err != nil {
+			t.Fatalf("Iter: %v", err)
+		}
+		it.Next()
+		it.Close()
+	}
+	ts.Close()
+	waitGoroutines(t, before)
+}
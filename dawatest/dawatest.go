@@ -0,0 +1,813 @@
+// Package dawatest provides a fake DAWA server for use in tests.
+//
+// The server is backed by fixtures added with the Add functions,
+// or loaded from CSV files in the format of the official downloads.
+// Queries are filtered using the same parameters as the query builders
+// in the dawa package emit, including polygon, cirkel and srid.
+// Results can be returned as GeoJSON with format=geojson.
+//
+// Example:
+//			srv := dawatest.NewServer()
+//			defer srv.Close()
+//			err := srv.LoadAdresserCSV(f)
+//
+//			client := srv.NewClient()
+//			all, err := client.NewAdresseQuery().Postnr("6792").All()
+package dawatest
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/dawa"
+)
+
+// Server is a fake DAWA server.
+// Use NewServer() to create a server.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	items    map[string][]map[string]interface{}
+	failures map[string]*failure
+	requests []string
+}
+
+type failure struct {
+	status int
+	err    dawa.RequestError
+	count  int
+}
+
+// filters contains the supported query parameters for each list type,
+// and the JSON fields they are matched against.
+// A field ending with "[]" is an array, and all entries are matched.
+var filters = map[string]map[string][]string{
+	"adresser": {
+		"id":                   {"id"},
+		"status":               {"status"},
+		"adgangsadresseid":     {"adgangsadresse.id"},
+		"vejkode":              {"adgangsadresse.vejstykke.kode"},
+		"vejnavn":              {"adgangsadresse.vejstykke.navn"},
+		"husnr":                {"adgangsadresse.husnr"},
+		"etage":                {"etage"},
+		"dør":                  {"dør"},
+		"supplerendebynavn":    {"adgangsadresse.supplerendebynavn"},
+		"postnr":               {"adgangsadresse.postnummer.nr"},
+		"kommunekode":          {"adgangsadresse.kommune.kode"},
+		"ejerlavkode":          {"adgangsadresse.ejerlav.kode"},
+		"matrikelnr":           {"adgangsadresse.matrikelnr"},
+		"esrejendomsnr":        {"adgangsadresse.esrejendomsnr"},
+		"kvhx":                 {"kvhx"},
+		"regionskode":          {"adgangsadresse.region.kode"},
+		"sognekode":            {"adgangsadresse.sogn.kode"},
+		"politikredskode":      {"adgangsadresse.politikreds.kode"},
+		"retskredskode":        {"adgangsadresse.retskreds.kode"},
+		"opstillingskredskode": {"adgangsadresse.opstillingskreds.kode"},
+		"zonekode":             {"adgangsadresse.zone"},
+		"q": {"adgangsadresse.vejstykke.navn", "adgangsadresse.husnr", "etage", "dør",
+			"adgangsadresse.supplerendebynavn", "adgangsadresse.postnummer.nr", "adgangsadresse.postnummer.navn"},
+	},
+	"adgangsadresser": {
+		"id":                   {"id"},
+		"status":               {"status"},
+		"vejkode":              {"vejstykke.kode"},
+		"vejnavn":              {"vejstykke.navn"},
+		"husnr":                {"husnr"},
+		"supplerendebynavn":    {"supplerendebynavn"},
+		"postnr":               {"postnummer.nr"},
+		"kommunekode":          {"kommune.kode"},
+		"ejerlavkode":          {"ejerlav.kode"},
+		"matrikelnr":           {"matrikelnr"},
+		"esrejendomsnr":        {"esrejendomsnr"},
+		"kvh":                  {"kvh"},
+		"regionskode":          {"region.kode"},
+		"sognekode":            {"sogn.kode"},
+		"politikredskode":      {"politikreds.kode"},
+		"retskredskode":        {"retskreds.kode"},
+		"opstillingskredskode": {"opstillingskreds.kode"},
+		"zonekode":             {"zone"},
+		"q":                    {"vejstykke.navn", "husnr", "supplerendebynavn", "postnummer.nr", "postnummer.navn"},
+	},
+	"postnumre": {
+		"nr":          {"nr"},
+		"navn":        {"navn"},
+		"kommunekode": {"kommuner[].kode"},
+		"q":           {"nr", "navn"},
+	},
+	"vejstykker": {
+		"kode":        {"kode"},
+		"kommunekode": {"kommune.kode"},
+		"navn":        {"navn"},
+		"postnr":      {"postnumre[].nr"},
+		"q":           {"navn"},
+	},
+	"supplerendebynavne": {
+		"navn":        {"navn"},
+		"kommunekode": {"kommuner[].kode"},
+		"postnr":      {"postnumre[].nr"},
+		"q":           {"navn"},
+	},
+}
+
+// listFilters are the filters supported by the generic list types.
+var listFilters = map[string][]string{
+	"kode": {"kode"},
+	"navn": {"navn"},
+	"q":    {"navn"},
+}
+
+// Supported list types, in addition to the types in filters.
+var listTypes = []string{"regioner", "kommuner", "sogne", "retskredse", "politikredse", "opstillingskredse", "valglandsdele", "ejerlav"}
+
+// coordinates contains the field of the WGS84 coordinates used for reverse lookups,
+// polygon and cirkel filters, and GeoJSON geometry.
+var coordinates = map[string]string{
+	"adresser":        "adgangsadresse.adgangspunkt.koordinater",
+	"adgangsadresser": "adgangspunkt.koordinater",
+}
+
+// zoner contains the zone names of the values of zonekode.
+var zoner = map[string]string{
+	"1": "Byzone",
+	"2": "Sommerhusområde",
+	"3": "Landzone",
+}
+
+// properties contains functions that return the GeoJSON properties of items.
+// The properties are the columns of the CSV files, like DAWA returns them.
+// Other list types have the values of the top level fields as properties.
+var properties = map[string]func(items []map[string]interface{}) ([]map[string]string, error){
+	"adresser":           csvProperties(dawa.ImportAdresserJSON),
+	"adgangsadresser":    csvProperties(dawa.ImportAdgangsAdresserJSON),
+	"postnumre":          csvProperties(dawa.ImportPostnumreJSON),
+	"vejstykker":         csvProperties(dawa.ImportVejstykkerJSON),
+	"supplerendebynavne": csvProperties(dawa.ImportSupplBynavnJSON),
+}
+
+// NewServer starts and returns a new fake DAWA server with no fixtures.
+// The caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		items:    make(map[string][]map[string]interface{}),
+		failures: make(map[string]*failure),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// NewClient returns a dawa.Client that sends queries to this server.
+func (s *Server) NewClient() *dawa.Client {
+	return &dawa.Client{HTTPClient: s.Client(), BaseURL: s.URL}
+}
+
+// AddList will add items to the specified list type.
+// The list types are the same as dawa.NewListQuery() accepts,
+// as well as "vejstykker" and "supplerendebynavne".
+// The items must be the type returned by the list, for instance dawa.Region for "regioner".
+func (s *Server) AddList(listType string, items ...interface{}) error {
+	if !knownType(listType) {
+		return fmt.Errorf("dawatest: unknown list type '%s'", listType)
+	}
+	maps := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		b, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		var m map[string]interface{}
+		err = json.Unmarshal(b, &m)
+		if err != nil {
+			return err
+		}
+		maps = append(maps, m)
+	}
+	s.mu.Lock()
+	s.items[listType] = append(s.items[listType], maps...)
+	s.mu.Unlock()
+	return nil
+}
+
+// AddAdresser will add the addresses to the server.
+func (s *Server) AddAdresser(a ...dawa.Adresse) {
+	s.mustAdd("adresser", len(a), func(i int) interface{} { return a[i] })
+}
+
+// AddAdgangsAdresser will add the addresses to the server.
+func (s *Server) AddAdgangsAdresser(a ...dawa.AdgangsAdresse) {
+	s.mustAdd("adgangsadresser", len(a), func(i int) interface{} { return a[i] })
+}
+
+// AddPostnumre will add the postal codes to the server.
+func (s *Server) AddPostnumre(p ...dawa.Postnummer) {
+	s.mustAdd("postnumre", len(p), func(i int) interface{} { return p[i] })
+}
+
+// AddVejstykker will add the road segments to the server.
+func (s *Server) AddVejstykker(v ...dawa.Vejstykke) {
+	s.mustAdd("vejstykker", len(v), func(i int) interface{} { return v[i] })
+}
+
+// AddSupplBynavne will add the supplementary town names to the server.
+func (s *Server) AddSupplBynavne(b ...dawa.SupplBynavn) {
+	s.mustAdd("supplerendebynavne", len(b), func(i int) interface{} { return b[i] })
+}
+
+// mustAdd will add n items returned by get to the list type.
+// Since the types are known, an error is a programming error.
+func (s *Server) mustAdd(listType string, n int, get func(i int) interface{}) {
+	items := make([]interface{}, n)
+	for i := range items {
+		items[i] = get(i)
+	}
+	if err := s.AddList(listType, items...); err != nil {
+		panic(err)
+	}
+}
+
+// LoadAdresserCSV will load "adresser" from a CSV file, using dawa.ImportAdresserCSV.
+func (s *Server) LoadAdresserCSV(in io.Reader) error {
+	iter, err := dawa.ImportAdresserCSV(in)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+	for {
+		a, err := iter.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		s.AddAdresser(*a)
+	}
+}
+
+// LoadAdgangsAdresserCSV will load "adgangsadresser" from a CSV file, using dawa.ImportAdgangsAdresserCSV.
+func (s *Server) LoadAdgangsAdresserCSV(in io.Reader) error {
+	iter, err := dawa.ImportAdgangsAdresserCSV(in)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+	for {
+		a, err := iter.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		s.AddAdgangsAdresser(*a)
+	}
+}
+
+// InjectError will make requests to the path return the error.
+// The path is without host and query, for instance "/adresser".
+// The error is returned as JSON with the specified HTTP status code,
+// so the client will return it as a dawa.RequestError.
+//
+// If count is > 0, only the next 'count' requests will fail, otherwise
+// all requests will fail until ClearErrors is called.
+func (s *Server) InjectError(path string, status int, err dawa.RequestError, count int) {
+	s.mu.Lock()
+	s.failures[path] = &failure{status: status, err: err, count: count}
+	s.mu.Unlock()
+}
+
+// ClearErrors will remove all injected errors.
+func (s *Server) ClearErrors() {
+	s.mu.Lock()
+	s.failures = make(map[string]*failure)
+	s.mu.Unlock()
+}
+
+// Requests returns the URLs of all requests received by the server,
+// without host, in the order they were received.
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func knownType(listType string) bool {
+	if _, ok := filters[listType]; ok {
+		return true
+	}
+	for _, t := range listTypes {
+		if t == listType {
+			return true
+		}
+	}
+	return false
+}
+
+// serve handles a single request.
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r.URL.RequestURI())
+	f := s.failures[r.URL.Path]
+	if f != nil && f.count > 0 {
+		f.count--
+		if f.count == 0 {
+			delete(s.failures, r.URL.Path)
+		}
+	}
+	s.mu.Unlock()
+	if f != nil {
+		writeError(w, f.status, f.err)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	listType := parts[0]
+	if !knownType(listType) || len(parts) > 2 || (len(parts) == 2 && parts[1] != "autocomplete" && parts[1] != "reverse") {
+		writeError(w, http.StatusNotFound, dawa.RequestError{Type: "ResourceNotFoundError", Title: "The resource was not found"})
+		return
+	}
+	s.mu.Lock()
+	items := s.items[listType]
+	s.mu.Unlock()
+
+	var result interface{}
+	var err error
+	if len(parts) == 2 && parts[1] == "reverse" {
+		result, err = reverse(listType, items, r)
+	} else {
+		var found []map[string]interface{}
+		found, err = query(listType, items, r)
+		result = found
+		if err == nil && r.URL.Query().Get("format") == "geojson" {
+			result, err = featureCollection(listType, found, r)
+		}
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, dawa.RequestError{Type: "QueryParameterFormatError", Title: err.Error()})
+		return
+	}
+	if result == nil {
+		writeError(w, http.StatusNotFound, dawa.RequestError{Type: "ResourceNotFoundError", Title: "The resource was not found"})
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	json.NewEncoder(w).Encode(result)
+}
+
+// writeError writes the error with the fields DAWA sends.
+// The URL of the error is set by the client.
+func writeError(w http.ResponseWriter, status int, rerr dawa.RequestError) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(struct {
+		Type    string        `json:"type"`
+		Title   string        `json:"title"`
+		Details []interface{} `json:"details"`
+	}{Type: rerr.Type, Title: rerr.Title, Details: rerr.Details})
+}
+
+// query returns the items matching the request parameters.
+func query(listType string, items []map[string]interface{}, r *http.Request) ([]map[string]interface{}, error) {
+	f, ok := filters[listType]
+	if !ok {
+		f = listFilters
+	}
+	params := r.URL.Query()
+	srid, err := requestSrid(r)
+	if err != nil {
+		return nil, err
+	}
+	// Sort the keys, so errors are consistent.
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := items
+	side, perSide := 1, 0
+	for _, key := range keys {
+		value := params.Get(key)
+		var err error
+		switch key {
+		case "noformat", "srid":
+			continue
+		case "format":
+			if value != "geojson" {
+				return nil, fmt.Errorf("invalid value for format: %q", value)
+			}
+			continue
+		case "side":
+			side, err = strconv.Atoi(value)
+			if err != nil || side < 1 {
+				return nil, fmt.Errorf("invalid value for side: %q", value)
+			}
+			continue
+		case "per_side":
+			perSide, err = strconv.Atoi(value)
+			if err != nil || perSide < 1 {
+				return nil, fmt.Errorf("invalid value for per_side: %q", value)
+			}
+			continue
+		}
+		var match func(m map[string]interface{}) bool
+		switch key {
+		case "polygon", "cirkel":
+			field, ok := coordinates[listType]
+			if !ok {
+				return nil, fmt.Errorf("dawatest: unsupported parameter %q for %s", key, listType)
+			}
+			if key == "polygon" {
+				match, err = matchPolygon(field, value, srid)
+			} else {
+				match, err = matchCircle(field, value, srid)
+			}
+			if err != nil {
+				return nil, err
+			}
+		case "stormodtagere":
+			if listType != "postnumre" {
+				return nil, fmt.Errorf("dawatest: unsupported parameter %q for %s", key, listType)
+			}
+			continue
+		default:
+			fields, ok := f[key]
+			if !ok {
+				return nil, fmt.Errorf("dawatest: unsupported parameter %q for %s", key, listType)
+			}
+			values := strings.Split(value, "|")
+			if key == "zonekode" {
+				for i, v := range values {
+					values[i] = zoner[v]
+				}
+			}
+			if key == "q" {
+				match = matchText(fields, value)
+			} else {
+				match = matchValues(fields[0], values)
+			}
+		}
+		filtered := make([]map[string]interface{}, 0, len(result))
+		for _, m := range result {
+			if match(m) {
+				filtered = append(filtered, m)
+			}
+		}
+		result = filtered
+	}
+	if listType == "postnumre" && params.Get("stormodtagere") != "true" {
+		// Stormodtager postnumre are only returned if requested.
+		filtered := make([]map[string]interface{}, 0, len(result))
+		for _, m := range result {
+			if s, _ := m["stormodtageradresser"].([]interface{}); len(s) == 0 {
+				filtered = append(filtered, m)
+			}
+		}
+		result = filtered
+	}
+	if perSide > 0 {
+		start := (side - 1) * perSide
+		if start > len(result) {
+			start = len(result)
+		}
+		end := start + perSide
+		if end > len(result) {
+			end = len(result)
+		}
+		result = result[start:end]
+	}
+	if result == nil {
+		result = []map[string]interface{}{}
+	}
+	return result, nil
+}
+
+// matchValues returns a function that matches items that have
+// one of the values in the field. An empty value matches an empty field.
+func matchValues(field string, values []string) func(m map[string]interface{}) bool {
+	return func(m map[string]interface{}) bool {
+		got := lookup(m, field)
+		if len(got) == 0 {
+			got = []string{""}
+		}
+		for _, g := range got {
+			for _, v := range values {
+				if g == v {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// matchText returns a function that matches items where all words
+// in the query are a prefix of a word in one of the fields.
+// The comparison is case insensitive.
+func matchText(fields []string, query string) func(m map[string]interface{}) bool {
+	words := strings.Fields(strings.ToLower(strings.Replace(query, "*", " ", -1)))
+	return func(m map[string]interface{}) bool {
+		var text []string
+		for _, f := range fields {
+			for _, v := range lookup(m, f) {
+				text = append(text, strings.Fields(strings.ToLower(v))...)
+			}
+		}
+	search:
+		for _, w := range words {
+			for _, t := range text {
+				if strings.HasPrefix(strings.Trim(t, ","), w) {
+					continue search
+				}
+			}
+			return false
+		}
+		return true
+	}
+}
+
+// reverse returns the item closest to the coordinates in the request.
+// Coordinates in ETRS89/UTM32 are transformed to WGS84 before the lookup.
+// For list types without coordinates, the first item is returned.
+func reverse(listType string, items []map[string]interface{}, r *http.Request) (interface{}, error) {
+	x, err := strconv.ParseFloat(r.URL.Query().Get("x"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for x: %v", err)
+	}
+	y, err := strconv.ParseFloat(r.URL.Query().Get("y"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for y: %v", err)
+	}
+	srid, err := requestSrid(r)
+	if err != nil {
+		return nil, err
+	}
+	p, _ := dawa.Point{X: x, Y: y, SRID: srid}.Transform(dawa.WGS84)
+	x, y = p.X, p.Y
+	field, ok := coordinates[listType]
+	if !ok {
+		if len(items) == 0 {
+			return nil, nil
+		}
+		return items[0], nil
+	}
+	var best map[string]interface{}
+	bestDist := math.Inf(1)
+	for _, m := range items {
+		c, ok := lookupValue(m, field).([]interface{})
+		if !ok || len(c) < 2 {
+			continue
+		}
+		cx, _ := c[0].(float64)
+		cy, _ := c[1].(float64)
+		d := (cx-x)*(cx-x) + (cy-y)*(cy-y)
+		if d < bestDist {
+			best, bestDist = m, d
+		}
+	}
+	if best == nil {
+		return nil, nil
+	}
+	return best, nil
+}
+
+// lookupValue returns the value of a dotted field.
+func lookupValue(m map[string]interface{}, field string) interface{} {
+	var v interface{} = m
+	for _, name := range strings.Split(field, ".") {
+		mv, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = mv[name]
+	}
+	return v
+}
+
+// lookup returns the values of a dotted field as strings.
+// If a part of the name ends with "[]", the values of all entries are returned.
+func lookup(m map[string]interface{}, field string) []string {
+	if i := strings.Index(field, "[]"); i >= 0 {
+		arr, _ := lookupValue(m, field[:i]).([]interface{})
+		rest := strings.TrimPrefix(field[i+2:], ".")
+		var ret []string
+		for _, e := range arr {
+			if rest == "" {
+				ret = append(ret, toString(e)...)
+				continue
+			}
+			if em, ok := e.(map[string]interface{}); ok {
+				ret = append(ret, lookup(em, rest)...)
+			}
+		}
+		return ret
+	}
+	return toString(lookupValue(m, field))
+}
+
+func toString(v interface{}) []string {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{v}
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}
+	case bool:
+		return []string{strconv.FormatBool(v)}
+	}
+	return []string{fmt.Sprint(v)}
+}
+
+// requestSrid returns the srid of the request, or WGS84 if it isn't set.
+func requestSrid(r *http.Request) (dawa.SRID, error) {
+	s := r.URL.Query().Get("srid")
+	if s == "" {
+		return dawa.WGS84, nil
+	}
+	srid, err := strconv.Atoi(s)
+	if err != nil || !dawa.SRID(srid).Valid() {
+		return 0, fmt.Errorf("invalid value for srid: %q", s)
+	}
+	return dawa.SRID(srid), nil
+}
+
+// itemPoint returns the WGS84 point in the coordinates field of the item.
+func itemPoint(m map[string]interface{}, field string) (dawa.Point, bool) {
+	c, ok := lookupValue(m, field).([]interface{})
+	if !ok || len(c) < 2 {
+		return dawa.Point{}, false
+	}
+	x, okx := c[0].(float64)
+	y, oky := c[1].(float64)
+	return dawa.WGS84Point(x, y), okx && oky
+}
+
+// matchPolygon returns a function that matches items with coordinates inside the polygon.
+// The polygon is in the format of the polygon parameter, in the koordinatsystem of srid.
+// Points in holes are not matched.
+func matchPolygon(field, value string, srid dawa.SRID) (func(m map[string]interface{}) bool, error) {
+	var c [][][]float64
+	if err := json.Unmarshal([]byte(value), &c); err != nil || len(c) == 0 {
+		return nil, fmt.Errorf("invalid value for polygon: %q", value)
+	}
+	rings := make([][]dawa.Point, len(c))
+	for i, ring := range c {
+		for _, xy := range ring {
+			if len(xy) < 2 {
+				return nil, fmt.Errorf("invalid value for polygon: %q", value)
+			}
+			p, _ := dawa.Point{X: xy[0], Y: xy[1], SRID: srid}.Transform(dawa.WGS84)
+			rings[i] = append(rings[i], p)
+		}
+	}
+	return func(m map[string]interface{}) bool {
+		p, ok := itemPoint(m, field)
+		if !ok || !inRing(p, rings[0]) {
+			return false
+		}
+		for _, hole := range rings[1:] {
+			if inRing(p, hole) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// inRing returns true if p is inside the ring, using the even-odd rule.
+func inRing(p dawa.Point, ring []dawa.Point) bool {
+	in := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+			in = !in
+		}
+	}
+	return in
+}
+
+// matchCircle returns a function that matches items with coordinates within the circle.
+// The circle is in the format of the cirkel parameter, "x,y,radius", with x and y
+// in the koordinatsystem of srid and the radius in meters.
+// Distances are measured in ETRS89/UTM32.
+func matchCircle(field, value string, srid dawa.SRID) (func(m map[string]interface{}) bool, error) {
+	v := strings.Split(value, ",")
+	var f [3]float64
+	var err error
+	for i := range f {
+		if len(v) != 3 {
+			break
+		}
+		if f[i], err = strconv.ParseFloat(v[i], 64); err != nil {
+			break
+		}
+	}
+	if len(v) != 3 || err != nil || f[2] <= 0 {
+		return nil, fmt.Errorf("invalid value for cirkel: %q", value)
+	}
+	center, _ := dawa.Point{X: f[0], Y: f[1], SRID: srid}.Transform(dawa.ETRS89UTM32)
+	return func(m map[string]interface{}) bool {
+		p, ok := itemPoint(m, field)
+		if !ok {
+			return false
+		}
+		p, _ = p.Transform(dawa.ETRS89UTM32)
+		return math.Hypot(p.X-center.X, p.Y-center.Y) <= f[2]
+	}, nil
+}
+
+// featureCollection returns the items as a GeoJSON feature collection.
+// Items with coordinates have a point geometry in the srid of the request.
+func featureCollection(listType string, items []map[string]interface{}, r *http.Request) (interface{}, error) {
+	srid, err := requestSrid(r)
+	if err != nil {
+		return nil, err
+	}
+	props := properties[listType]
+	if props == nil {
+		props = scalarProperties
+	}
+	flat, err := props(items)
+	if err != nil {
+		return nil, err
+	}
+	type geometry struct {
+		Type        string    `json:"type"`
+		Coordinates []float64 `json:"coordinates"`
+	}
+	type feature struct {
+		Type       string            `json:"type"`
+		Geometry   *geometry         `json:"geometry"`
+		Properties map[string]string `json:"properties"`
+	}
+	features := make([]feature, len(items))
+	for i, m := range items {
+		features[i] = feature{Type: "Feature", Properties: flat[i]}
+		if p, ok := itemPoint(m, coordinates[listType]); ok {
+			p, _ = p.Transform(srid)
+			features[i].Geometry = &geometry{Type: "Point", Coordinates: []float64{p.X, p.Y}}
+		}
+	}
+	crs := map[string]interface{}{"type": "name", "properties": map[string]string{"name": "EPSG:" + srid.String()}}
+	return map[string]interface{}{"type": "FeatureCollection", "crs": crs, "features": features}, nil
+}
+
+// csvProperties returns a function that returns the CSV columns of items as properties.
+// The items are imported with imp, and written with dawa.ExportCSV.
+// Empty columns are left out, like DAWA sends null.
+func csvProperties[T any](imp func(io.Reader) (*dawa.Iter[T], error)) func(items []map[string]interface{}) ([]map[string]string, error) {
+	return func(items []map[string]interface{}) ([]map[string]string, error) {
+		b, err := json.Marshal(items)
+		if err != nil {
+			return nil, err
+		}
+		iter, err := imp(bytes.NewReader(b))
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := dawa.ExportCSV(&buf, iter); err != nil {
+			return nil, err
+		}
+		rows, err := csv.NewReader(&buf).ReadAll()
+		if err != nil {
+			return nil, err
+		}
+		ret := make([]map[string]string, len(items))
+		for i := range ret {
+			ret[i] = make(map[string]string)
+			for j, v := range rows[i+1] {
+				if v != "" {
+					ret[i][rows[0][j]] = v
+				}
+			}
+		}
+		return ret, nil
+	}
+}
+
+// scalarProperties returns the top level string, number and bool fields of the items as properties.
+func scalarProperties(items []map[string]interface{}) ([]map[string]string, error) {
+	ret := make([]map[string]string, len(items))
+	for i, m := range items {
+		ret[i] = make(map[string]string)
+		for k, v := range m {
+			switch v.(type) {
+			case string, float64, bool:
+				ret[i][k] = toString(v)[0]
+			}
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,298 @@
+package dawatest
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/dawa"
+)
+
+var adresser_csv = `id,status,oprettet,ændret,vejkode,vejnavn,husnr,etage,dør,supplerendebynavn,postnr,postnrnavn,kommunekode,kommunenavn,ejerlavkode,ejerlavnavn,matrikelnr,esrejendomsnr,etrs89koordinat_øst,etrs89koordinat_nord,wgs84koordinat_bredde,wgs84koordinat_længde,nøjagtighed,kilde,tekniskstandard,tekstretning,ddkn_m100,ddkn_km1,ddkn_km10,adressepunktændringsdato,adgangsadresseid,adgangsadresse_status,adgangsadresse_oprettet,adgangsadresse_ændret,kvhx,regionskode,regionsnavn,sognekode,sognenavn,politikredskode,politikredsnavn,retskredskode,retskredsnavn,opstillingskredskode,opstillingskredsnavn,zone
+0a3f50b7-6545-32b8-e044-0003ba298018,1,2000-02-05T18:09:56.000,2000-02-16T21:58:33.000,0001,A Hansensvej,6,,,Vråby,6792,Rømø,0550,Tønder,1470852,"Kirkeby, Rømø",76,9097,470620,6105713,55.0972751504817,8.53959543878291,A,5,UF,200,100m_61057_4706,1km_6105_470,10km_610_47,2004-10-08T00:00:00.000,0a3f508c-3307-32b8-e044-0003ba298018,1,2000-02-05T18:09:56.000,2009-11-24T03:15:25.000,05500001___6_______,1083,Region Syddanmark,9062,Rømø,1464,Syd- og Sønderjyllands Politi,1147,Retten i Sønderborg,0051,Tønder,Landzone
+0a3f50b7-6544-32b8-e044-0003ba298018,1,2000-02-05T18:09:53.000,2000-02-16T21:58:33.000,0001,A Hansensvej,5,,,Vråby,6792,Rømø,0550,Tønder,1470852,"Kirkeby, Rømø",720,8848,470531,6105718,55.0973148017997,8.53820028085436,A,5,UF,200,100m_61057_4705,1km_6105_470,10km_610_47,2004-10-08T00:00:00.000,0a3f508c-3306-32b8-e044-0003ba298018,1,2000-02-05T18:09:53.000,2009-11-24T03:15:25.000,05500001___5_______,1083,Region Syddanmark,9062,Rømø,1464,Syd- og Sønderjyllands Politi,1147,Retten i Sønderborg,0051,Tønder,Landzone
+0a3f50b7-6547-32b8-e044-0003ba298018,1,2000-02-05T18:09:49.000,2004-02-12T16:05:28.000,0001,A Hansensvej,8,1,tv,Vråby,6792,Rømø,0550,Tønder,1470852,"Kirkeby, Rømø",770,8559,470587,6105811,55.0981538216665,8.5390681928166,A,5,UF,200,100m_61058_4705,1km_6105_470,10km_610_47,2004-10-08T00:00:00.000,0a3f508c-3309-32b8-e044-0003ba298018,1,2000-02-05T18:09:49.000,2009-11-24T03:15:25.000,05500001___8_______,1083,Region Syddanmark,9062,Rømø,1464,Syd- og Sønderjyllands Politi,1147,Retten i Sønderborg,0051,Tønder,Landzone
+`
+
+func newServer(t *testing.T) *Server {
+	srv := NewServer()
+	if err := srv.LoadAdresserCSV(strings.NewReader(adresser_csv)); err != nil {
+		srv.Close()
+		t.Fatalf("LoadAdresserCSV: %v", err)
+	}
+	srv.AddPostnumre(dawa.Postnummer{Nr: "6792", Navn: "Rømø"}, dawa.Postnummer{Nr: "2400", Navn: "København NV"})
+	if err := srv.AddList("regioner", dawa.Region{RegionRef: dawa.RegionRef{Kode: "1083", Navn: "Region Syddanmark"}}); err != nil {
+		srv.Close()
+		t.Fatalf("AddList: %v", err)
+	}
+	return srv
+}
+
+func TestServerQuery(t *testing.T) {
+	srv := newServer(t)
+	defer srv.Close()
+	c := srv.NewClient()
+
+	tests := []struct {
+		q    *dawa.AdresseQuery
+		want int
+	}{
+		{c.NewAdresseQuery(), 3},
+		{c.NewAdresseQuery().Postnr("6792"), 3},
+		{c.NewAdresseQuery().Postnr("2400"), 0},
+		{c.NewAdresseQuery().Husnr("5", "6"), 2},
+		{c.NewAdresseQuery().Etage(), 2},
+		{c.NewAdresseQuery().Etage("1").Dør("tv"), 1},
+		{c.NewAdresseQuery().Q("a hansen* 8"), 1},
+		{c.NewAdresseComplete().Q("hansensvej"), 3},
+		{c.NewAdresseQuery().Side(2).PerSide(2), 1},
+	}
+	for _, test := range tests {
+		all, err := test.q.All()
+		if err != nil {
+			t.Fatalf("%s: %v", test.q.URL(), err)
+		}
+		if len(all) != test.want {
+			t.Fatalf("%s: expected %d results, got %d", test.q.URL(), test.want, len(all))
+		}
+	}
+
+	a, err := c.NewAdresseQuery().ID("0a3f50b7-6547-32b8-e044-0003ba298018").First()
+	if err != nil {
+		t.Fatalf("First: %v", err)
+	}
+	if a.Adgangsadresse.Vejstykke.Navn != "A Hansensvej" || a.Historik.Oprettet.Time().Year() != 2000 {
+		t.Fatalf("Unexpected result: %+v", a)
+	}
+
+	p, err := c.NewPostnrQuery().Nr("2400").First()
+	if err != nil || p.Navn != "København NV" {
+		t.Fatalf("Postnr: %+v, %v", p, err)
+	}
+
+	r, err := dawa.ClientListQueryOf[dawa.Region](c, false).Kode("1083").First()
+	if err != nil || r.Navn != "Region Syddanmark" {
+		t.Fatalf("Region: %+v, %v", r, err)
+	}
+
+	// Unsupported parameters are reported with the fields DAWA sends.
+	resp, err := srv.Client().Get(srv.URL + "/adresser?ukendt=1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest || len(body) != 3 || body["type"] != "QueryParameterFormatError" {
+		t.Fatalf("Unexpected error: %d, %v", resp.StatusCode, body)
+	}
+	for _, k := range []string{"title", "details"} {
+		if _, ok := body[k]; !ok {
+			t.Fatalf("Missing %q in error: %v", k, body)
+		}
+	}
+}
+
+func TestServerGeo(t *testing.T) {
+	srv := newServer(t)
+	defer srv.Close()
+	c := srv.NewClient()
+
+	// Rings around husnr 5 and 6, in ETRS89/UTM32 and WGS84.
+	etrs := []dawa.Point{dawa.ETRS89Point(470500, 6105700), dawa.ETRS89Point(470700, 6105700), dawa.ETRS89Point(470700, 6105750), dawa.ETRS89Point(470500, 6105750), dawa.ETRS89Point(470500, 6105700)}
+	wgs := []dawa.Point{dawa.WGS84Point(8.535, 55.097), dawa.WGS84Point(8.545, 55.097), dawa.WGS84Point(8.545, 55.098), dawa.WGS84Point(8.535, 55.098), dawa.WGS84Point(8.535, 55.097)}
+	hole := []dawa.Point{dawa.ETRS89Point(470600, 6105705), dawa.ETRS89Point(470650, 6105705), dawa.ETRS89Point(470650, 6105725), dawa.ETRS89Point(470600, 6105725), dawa.ETRS89Point(470600, 6105705)}
+
+	tests := []struct {
+		q    *dawa.AdresseQuery
+		want int
+	}{
+		{c.NewAdresseQuery().Polygon(wgs), 2},
+		{c.NewAdresseQuery().Srid(dawa.ETRS89UTM32).Polygon(etrs), 2},
+		{c.NewAdresseQuery().Srid(dawa.ETRS89UTM32).Polygon(etrs, hole), 1},
+		{c.NewAdresseQuery().Cirkel(dawa.ETRS89Point(470531, 6105718), 10), 1},
+		{c.NewAdresseQuery().Cirkel(dawa.ETRS89Point(470531, 6105718), 150), 3},
+		{c.NewAdresseQuery().Srid(dawa.ETRS89UTM32).Cirkel(dawa.ETRS89Point(470620, 6105713), 1), 1},
+		{c.NewAdresseQuery().Zonekode("3"), 3},
+		{c.NewAdresseQuery().Zonekode("1", "2"), 0},
+	}
+	for _, test := range tests {
+		all, err := test.q.All()
+		if err != nil {
+			t.Fatalf("%s: %v", test.q.URL(), err)
+		}
+		if len(all) != test.want {
+			t.Fatalf("%s: expected %d results, got %d", test.q.URL(), test.want, len(all))
+		}
+	}
+
+	// GeoJSON is returned in the srid of the query.
+	iter, err := c.NewAdresseQuery().Srid(dawa.ETRS89UTM32).Husnr("5").GeoJSONTyped()
+	if err != nil {
+		t.Fatalf("GeoJSONTyped: %v", err)
+	}
+	f, err := iter.Next()
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if f.Properties.Adgangsadresse.Husnr != "5" || f.Properties.Adgangsadresse.Vejstykke.Navn != "A Hansensvej" {
+		t.Fatalf("Unexpected properties: %+v", f.Properties)
+	}
+	p := f.Geometry.Point
+	if f.Geometry.Type != dawa.GeometryPoint || p.SRID != dawa.ETRS89UTM32 || math.Abs(p.X-470531) > 0.5 || math.Abs(p.Y-6105718) > 0.5 {
+		t.Fatalf("Unexpected geometry: %#v", f.Geometry)
+	}
+	if _, err := iter.Next(); err != io.EOF {
+		t.Fatalf("Expected io.EOF, got %v", err)
+	}
+
+	fc, err := c.NewPostnrQuery().Nr("2400").GeoJSON()
+	if err != nil || len(fc.Features) != 1 || fc.Features[0].Properties["navn"] != "København NV" {
+		t.Fatalf("GeoJSON: %+v, %v", fc, err)
+	}
+}
+
+// TestServerBuilders runs every builder method against the server.
+func TestServerBuilders(t *testing.T) {
+	srv := newServer(t)
+	defer srv.Close()
+	c := srv.NewClient()
+
+	ring := []dawa.Point{dawa.WGS84Point(8, 55), dawa.WGS84Point(9, 55), dawa.WGS84Point(9, 56), dawa.WGS84Point(8, 55)}
+	args := map[reflect.Type]reflect.Value{
+		reflect.TypeOf(""):                reflect.ValueOf("1"),
+		reflect.TypeOf(0):                 reflect.ValueOf(1),
+		reflect.TypeOf(false):             reflect.ValueOf(true),
+		reflect.TypeOf(0.0):               reflect.ValueOf(100.0),
+		reflect.TypeOf(dawa.SRID(0)):      reflect.ValueOf(dawa.ETRS89UTM32),
+		reflect.TypeOf(dawa.Point{}):      reflect.ValueOf(dawa.WGS84Point(8.5, 55.1)),
+		reflect.TypeOf([]dawa.Point(nil)): reflect.ValueOf(ring),
+	}
+	builders := map[string]func() interface{}{
+		"AdresseQuery":           func() interface{} { return c.NewAdresseQuery() },
+		"AdresseComplete":        func() interface{} { return c.NewAdresseComplete() },
+		"AdgangsAdresseQuery":    func() interface{} { return c.NewAdgangsAdresseQuery() },
+		"AdgangsAdresseComplete": func() interface{} { return c.NewAdgangsAdresseComplete() },
+		"PostnrQuery":            func() interface{} { return c.NewPostnrQuery() },
+		"PostnrComplete":         func() interface{} { return c.NewPostnrComplete() },
+		"VejstykkeQuery":         func() interface{} { return c.NewVejstykkeQuery() },
+		"VejstykkeComplete":      func() interface{} { return c.NewVejstykkeComplete() },
+		"SupplBynavnQuery":       func() interface{} { return c.NewSupplBynavnQuery() },
+		"SupplBynavnComplete":    func() interface{} { return c.NewSupplBynavnComplete() },
+		"ListQuery":              func() interface{} { return c.NewListQuery("regioner", false) },
+		"TypedListQuery":         func() interface{} { return dawa.ClientListQueryOf[dawa.Region](c, false) },
+	}
+	for name, build := range builders {
+		typ := reflect.TypeOf(build())
+		for i := 0; i < typ.NumMethod(); i++ {
+			m := typ.Method(i)
+			if m.Type.NumOut() != 1 || m.Type.Out(0) != typ {
+				continue
+			}
+			q := reflect.ValueOf(build())
+			in := []reflect.Value{q}
+			for j := 1; j < m.Type.NumIn(); j++ {
+				at := m.Type.In(j)
+				if m.Type.IsVariadic() && j == m.Type.NumIn()-1 {
+					at = at.Elem()
+				}
+				v, ok := args[at]
+				if !ok {
+					t.Fatalf("%s.%s: no argument of type %v", name, m.Name, at)
+				}
+				in = append(in, v)
+			}
+			m.Func.Call(in)
+			for _, terminal := range []string{"All", "Iter", "GeoJSON", "GeoJSONTyped"} {
+				tm := q.MethodByName(terminal)
+				if !tm.IsValid() {
+					continue
+				}
+				out := tm.Call(nil)
+				err, _ := out[len(out)-1].Interface().(error)
+				if next := out[0].MethodByName("Next"); err == nil && next.IsValid() {
+					for err == nil {
+						out := next.Call(nil)
+						err, _ = out[len(out)-1].Interface().(error)
+					}
+					if err == io.EOF {
+						err = nil
+					}
+				}
+				if err != nil {
+					t.Errorf("%s.%s.%s: %v", name, m.Name, terminal, err)
+				}
+			}
+		}
+	}
+}
+
+func TestServerReverse(t *testing.T) {
+	srv := newServer(t)
+	defer srv.Close()
+	c := srv.NewClient()
+
+	iter, err := dawa.ClientReverseQueryOf[dawa.Adresse](c, dawa.WGS84Point(8.53820028085436, 55.0973148017997))
+	if err != nil {
+		t.Fatalf("Reverse: %v", err)
+	}
+	a, err := iter.Next()
+	if err != nil || a.Adgangsadresse.Husnr != "5" {
+		t.Fatalf("Unexpected result: %+v, %v", a, err)
+	}
+
+	// ETRS89/UTM32 koordinater are transformed by the server.
+	iter, err = dawa.ClientReverseQueryOf[dawa.Adresse](c, dawa.ETRS89Point(470531, 6105718))
+	if err != nil {
+		t.Fatalf("Reverse: %v", err)
+	}
+	a, err = iter.Next()
+	if err != nil || a.Adgangsadresse.Husnr != "5" {
+		t.Fatalf("Unexpected result: %+v, %v", a, err)
+	}
+
+	li, err := c.NewReverseQuery("regioner", dawa.WGS84Point(12, 55))
+	if err != nil {
+		t.Fatalf("Reverse: %v", err)
+	}
+	if r, err := li.NextRegion(); err != nil || r.Kode != "1083" {
+		t.Fatalf("Unexpected result: %+v, %v", r, err)
+	}
+}
+
+func TestServerInjectError(t *testing.T) {
+	srv := newServer(t)
+	defer srv.Close()
+	c := srv.NewClient()
+
+	srv.InjectError("/adresser", http.StatusBadRequest, dawa.RequestError{Type: "QueryParameterFormatError", Title: "Invalid"}, 1)
+	_, err := c.NewAdresseQuery().All()
+	rerr, ok := err.(dawa.RequestError)
+	if !ok || rerr.Type != "QueryParameterFormatError" || rerr.Title != "Invalid" {
+		t.Fatalf("Expected RequestError, got %T: %v", err, err)
+	}
+	// Only the first request fails.
+	if _, err := c.NewAdresseQuery().First(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	srv.InjectError("/postnumre", http.StatusServiceUnavailable, dawa.RequestError{Type: "Unavailable"}, 0)
+	for i := 0; i < 2; i++ {
+		if _, err := c.NewPostnrQuery().First(); err == nil || err == io.EOF {
+			t.Fatalf("Expected error, got %v", err)
+		}
+	}
+	srv.ClearErrors()
+	if _, err := c.NewPostnrQuery().First(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if n := len(srv.Requests()); n != 5 {
+		t.Fatalf("Expected 5 requests, got %d", n)
+	}
+}
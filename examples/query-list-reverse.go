@@ -9,8 +9,8 @@ import (
 )
 
 func main() {
-	// Ask for region at 12.5851471984198 y=55.6832383751223
-	iter, err := dawa.NewReverseQuery("kommuner", 12.5851471984198, 55.6832383751223, "")
+	// Ask for kommune at længde 12.5851471984198, bredde 55.6832383751223
+	iter, err := dawa.NewReverseQuery("kommuner", dawa.WGS84Point(12.5851471984198, 55.6832383751223))
 	if err != nil {
 		panic(err)
 	}
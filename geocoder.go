@@ -0,0 +1,316 @@
+package dawa
+
+import (
+	"container/heap"
+	"io"
+	"math"
+	"sort"
+)
+
+// earthRadius is the mean radius of the earth in meters.
+const earthRadius = 6371008.8
+
+// ReverseGeocoder is an offline reverse geocoder for adgangsadresser.
+//
+// It uses a k-d tree built from the coordinates of the adgangsadresser,
+// so lookups do not require any network calls.
+// The WGS84 Adgangspunkt.Koordinater are used, or Adgangspunkt.Etrs89Koordinater
+// if they are not set. Points given to lookups can be WGS84 or ETRS89/UTM32.
+//
+// Distances are great-circle distances in meters.
+// A ReverseGeocoder is safe for concurrent use.
+type ReverseGeocoder struct {
+	items []AdgangsAdresse
+	tree  []kdPoint
+}
+
+// ReverseResult is a result from a ReverseGeocoder.
+type ReverseResult struct {
+	AdgangsAdresse AdgangsAdresse
+	Afstand        float64 // Afstand til punktet i meter.
+}
+
+// NewReverseGeocoder returns a reverse geocoder for the supplied adgangsadresser.
+// Entries without coordinates are ignored.
+func NewReverseGeocoder(items []AdgangsAdresse) *ReverseGeocoder {
+	g := &ReverseGeocoder{items: make([]AdgangsAdresse, 0, len(items))}
+	for _, a := range items {
+		g.add(a)
+	}
+	g.build()
+	return g
+}
+
+// LoadReverseGeocoder returns a reverse geocoder for all entries in the iterator.
+// The iterator can be returned by ImportAdgangsAdresserJSON or a query.
+// Entries without coordinates are ignored.
+// The iterator is closed when the function returns.
+func LoadReverseGeocoder(iter *AdgangsAdresseIter) (*ReverseGeocoder, error) {
+	defer iter.Close()
+	g := &ReverseGeocoder{}
+	for {
+		a, err := iter.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		g.add(*a)
+	}
+	g.build()
+	return g, nil
+}
+
+// Len returns the number of adgangsadresser in the geocoder.
+func (g *ReverseGeocoder) Len() int {
+	return len(g.items)
+}
+
+// Reverse returns the adgangsadresse closest to the point,
+// like a reverse query on "adgangsadresser" would.
+// If the geocoder is empty (nil, io.EOF) is returned.
+func (g *ReverseGeocoder) Reverse(p Point) (*AdgangsAdresse, error) {
+	res, err := g.Nearest(p, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, io.EOF
+	}
+	return &res[0].AdgangsAdresse, nil
+}
+
+// Nearest returns the n adgangsadresser closest to the point,
+// ordered by distance.
+// If several entries have the same distance, they are returned in the order they were added.
+// An error is returned if the SRID of the point isn't supported.
+func (g *ReverseGeocoder) Nearest(p Point, n int) ([]ReverseResult, error) {
+	q, err := geocoderVector(p)
+	if err != nil || n <= 0 || len(g.tree) == 0 {
+		return nil, err
+	}
+	s := kdSearch{q: q, n: n}
+	s.nearest(g.tree, 0)
+	return g.results(s.found), nil
+}
+
+// Within returns the adgangsadresser within radius meters of the point,
+// ordered by distance.
+// If several entries have the same distance, they are returned in the order they were added.
+// An error is returned if the SRID of the point isn't supported.
+func (g *ReverseGeocoder) Within(p Point, radius float64) ([]ReverseResult, error) {
+	q, err := geocoderVector(p)
+	if err != nil || radius < 0 || len(g.tree) == 0 {
+		return nil, err
+	}
+	s := kdSearch{q: q, maxDist: chordDist(radius)}
+	s.within(g.tree, 0)
+	return g.results(s.found), nil
+}
+
+// geocoderVector returns the point on the unit sphere for p.
+func geocoderVector(p Point) ([3]float64, error) {
+	w, err := p.Transform(WGS84)
+	if err != nil {
+		return [3]float64{}, err
+	}
+	return unitVector(w.X, w.Y), nil
+}
+
+// results returns the found entries ordered by distance.
+func (g *ReverseGeocoder) results(found kdHeap) []ReverseResult {
+	sort.Slice(found, func(i, j int) bool { return found[i].before(found[j]) })
+	ret := make([]ReverseResult, len(found))
+	for i, f := range found {
+		ret[i] = ReverseResult{AdgangsAdresse: g.items[f.i], Afstand: arcDist(f.d)}
+	}
+	return ret
+}
+
+// add will add the adgangsadresse if it has coordinates.
+func (g *ReverseGeocoder) add(a AdgangsAdresse) {
+	w, ok := a.Adgangspunkt.WGS84()
+	if !ok || (w.X == 0 && w.Y == 0) {
+		return
+	}
+	g.tree = append(g.tree, kdPoint{p: unitVector(w.X, w.Y), i: len(g.items)})
+	g.items = append(g.items, a)
+}
+
+// build will arrange the points as a k-d tree.
+func (g *ReverseGeocoder) build() {
+	kdBuild(g.tree, 0)
+}
+
+// unitVector returns the point on the unit sphere for the longitude and latitude in degrees.
+func unitVector(lon, lat float64) [3]float64 {
+	lo, la := lon*math.Pi/180, lat*math.Pi/180
+	return [3]float64{math.Cos(la) * math.Cos(lo), math.Cos(la) * math.Sin(lo), math.Sin(la)}
+}
+
+// chordDist returns the squared chord length on the unit sphere for a great-circle distance in meters.
+func chordDist(meters float64) float64 {
+	angle := meters / earthRadius
+	if angle >= math.Pi {
+		return 4
+	}
+	c := 2 * math.Sin(angle/2)
+	return c * c
+}
+
+// arcDist returns the great-circle distance in meters for a squared chord length on the unit sphere.
+func arcDist(d2 float64) float64 {
+	c := math.Sqrt(d2) / 2
+	if c > 1 {
+		c = 1
+	}
+	return 2 * math.Asin(c) * earthRadius
+}
+
+type kdPoint struct {
+	p [3]float64
+	i int // index of the entry
+}
+
+func dist2(a, b [3]float64) float64 {
+	x, y, z := a[0]-b[0], a[1]-b[1], a[2]-b[2]
+	return x*x + y*y + z*z
+}
+
+// kdBuild will arrange pts as an implicit k-d tree.
+// The median of each range is the node, the lower half is the left subtree
+// and the upper half is the right subtree. The split axis is depth modulo 3.
+func kdBuild(pts []kdPoint, depth int) {
+	if len(pts) <= 1 {
+		return
+	}
+	axis := depth % 3
+	mid := len(pts) / 2
+	kdSelect(pts, mid, axis)
+	kdBuild(pts[:mid], depth+1)
+	kdBuild(pts[mid+1:], depth+1)
+}
+
+// kdSelect will partially sort pts, so the element at k is in its sorted position,
+// all elements before are <= and all after are >= on the axis.
+func kdSelect(pts []kdPoint, k, axis int) {
+	lo, hi := 0, len(pts)-1
+	for lo < hi {
+		// Median of three as pivot.
+		mid := lo + (hi-lo)/2
+		if pts[mid].p[axis] < pts[lo].p[axis] {
+			pts[mid], pts[lo] = pts[lo], pts[mid]
+		}
+		if pts[hi].p[axis] < pts[lo].p[axis] {
+			pts[hi], pts[lo] = pts[lo], pts[hi]
+		}
+		if pts[hi].p[axis] < pts[mid].p[axis] {
+			pts[hi], pts[mid] = pts[mid], pts[hi]
+		}
+		pivot := pts[mid].p[axis]
+		i, j := lo, hi
+		for i <= j {
+			for pts[i].p[axis] < pivot {
+				i++
+			}
+			for pts[j].p[axis] > pivot {
+				j--
+			}
+			if i <= j {
+				pts[i], pts[j] = pts[j], pts[i]
+				i++
+				j--
+			}
+		}
+		switch {
+		case k <= j:
+			hi = j
+		case k >= i:
+			lo = i
+		default:
+			return
+		}
+	}
+}
+
+// kdFound is a point found by a search.
+type kdFound struct {
+	d float64 // squared distance
+	i int
+}
+
+// before returns true if f should be returned before o.
+// Ties are ordered by index, so the order is stable.
+func (f kdFound) before(o kdFound) bool {
+	if f.d != o.d {
+		return f.d < o.d
+	}
+	return f.i < o.i
+}
+
+// kdHeap is a max-heap of found points, with the farthest first.
+type kdHeap []kdFound
+
+func (h kdHeap) Len() int            { return len(h) }
+func (h kdHeap) Less(i, j int) bool  { return h[j].before(h[i]) }
+func (h kdHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *kdHeap) Push(x interface{}) { *h = append(*h, x.(kdFound)) }
+func (h *kdHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// kdSearch contains the state of a search.
+type kdSearch struct {
+	q       [3]float64
+	n       int     // max results for nearest
+	maxDist float64 // max squared distance for within
+	found   kdHeap
+}
+
+func (s *kdSearch) nearest(pts []kdPoint, depth int) {
+	if len(pts) == 0 {
+		return
+	}
+	mid := len(pts) / 2
+	p := pts[mid]
+	f := kdFound{d: dist2(s.q, p.p), i: p.i}
+	if len(s.found) < s.n {
+		heap.Push(&s.found, f)
+	} else if f.before(s.found[0]) {
+		s.found[0] = f
+		heap.Fix(&s.found, 0)
+	}
+	axis := depth % 3
+	diff := s.q[axis] - p.p[axis]
+	first, second := pts[:mid], pts[mid+1:]
+	if diff > 0 {
+		first, second = second, first
+	}
+	s.nearest(first, depth+1)
+	if len(s.found) < s.n || diff*diff <= s.found[0].d {
+		s.nearest(second, depth+1)
+	}
+}
+
+func (s *kdSearch) within(pts []kdPoint, depth int) {
+	if len(pts) == 0 {
+		return
+	}
+	mid := len(pts) / 2
+	p := pts[mid]
+	if d := dist2(s.q, p.p); d <= s.maxDist {
+		s.found = append(s.found, kdFound{d: d, i: p.i})
+	}
+	axis := depth % 3
+	diff := s.q[axis] - p.p[axis]
+	if diff <= 0 || diff*diff <= s.maxDist {
+		s.within(pts[:mid], depth+1)
+	}
+	if diff >= 0 || diff*diff <= s.maxDist {
+		s.within(pts[mid+1:], depth+1)
+	}
+}
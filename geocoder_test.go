@@ -0,0 +1,148 @@
+package dawa
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// haversine returns the great-circle distance in meters.
+func haversine(lon1, lat1, lon2, lat2 float64) float64 {
+	rad := math.Pi / 180
+	dlat, dlon := (lat2-lat1)*rad, (lon2-lon1)*rad
+	a := math.Sin(dlat/2)*math.Sin(dlat/2) + math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dlon/2)*math.Sin(dlon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
+
+func randomAdgangsAdresser(rng *rand.Rand, n int) []AdgangsAdresse {
+	ret := make([]AdgangsAdresse, n)
+	for i := range ret {
+		ret[i].ID = fmt.Sprint(i)
+		// Points in Denmark
+		ret[i].Adgangspunkt.Koordinater = []float64{8 + rng.Float64()*5, 54.5 + rng.Float64()*3}
+	}
+	return ret
+}
+
+// bruteForce returns all entries ordered by distance.
+func bruteForce(items []AdgangsAdresse, x, y float64) []ReverseResult {
+	ret := make([]ReverseResult, len(items))
+	for i, a := range items {
+		c := a.Adgangspunkt.Koordinater
+		ret[i] = ReverseResult{AdgangsAdresse: a, Afstand: haversine(x, y, c[0], c[1])}
+	}
+	sort.SliceStable(ret, func(i, j int) bool { return ret[i].Afstand < ret[j].Afstand })
+	return ret
+}
+
+func TestReverseGeocoder(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	items := randomAdgangsAdresser(rng, 2000)
+	g := NewReverseGeocoder(items)
+	if g.Len() != len(items) {
+		t.Fatalf("Expected %d entries, got %d", len(items), g.Len())
+	}
+	for test := 0; test < 100; test++ {
+		x, y := 8+rng.Float64()*5, 54.5+rng.Float64()*3
+		want := bruteForce(items, x, y)
+
+		got, err := g.Nearest(WGS84Point(x, y), 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 10 {
+			t.Fatalf("Expected 10 results, got %d", len(got))
+		}
+		for i := range got {
+			if got[i].AdgangsAdresse.ID != want[i].AdgangsAdresse.ID {
+				t.Fatalf("Nearest %d: got %s, expected %s", i, got[i].AdgangsAdresse.ID, want[i].AdgangsAdresse.ID)
+			}
+			if math.Abs(got[i].Afstand-want[i].Afstand) > 0.01 {
+				t.Fatalf("Nearest %d: got distance %v, expected %v", i, got[i].Afstand, want[i].Afstand)
+			}
+		}
+
+		radius := rng.Float64() * 20000
+		within, err := g.Within(WGS84Point(x, y), radius)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n := 0
+		for n < len(want) && want[n].Afstand <= radius {
+			n++
+		}
+		if len(within) != n {
+			t.Fatalf("Within %v: got %d results, expected %d", radius, len(within), n)
+		}
+		for i := range within {
+			if within[i].AdgangsAdresse.ID != want[i].AdgangsAdresse.ID {
+				t.Fatalf("Within %d: got %s, expected %s", i, within[i].AdgangsAdresse.ID, want[i].AdgangsAdresse.ID)
+			}
+		}
+	}
+}
+
+func TestReverseGeocoderLoad(t *testing.T) {
+	iter, err := ImportAdgangsAdresserJSON(bytes.NewBufferString(adgangs_json_input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := LoadReverseGeocoder(iter)
+	if err != nil {
+		t.Fatalf("LoadReverseGeocoder: %v", err)
+	}
+	a, err := g.Reverse(WGS84Point(12.5610912697286, 55.6708378041398))
+	if err != nil {
+		t.Fatalf("Reverse: %v", err)
+	}
+	if a.Husnr != "28" {
+		t.Fatalf("Unexpected result: %+v", a)
+	}
+
+	// Entries without coordinates are ignored.
+	empty := NewReverseGeocoder([]AdgangsAdresse{{ID: "a"}})
+	if _, err := empty.Reverse(WGS84Point(12, 55)); err != io.EOF {
+		t.Fatalf("Expected io.EOF, got %v", err)
+	}
+	if r, err := empty.Within(WGS84Point(12, 55), 1000); err != nil || len(r) != 0 {
+		t.Fatalf("Expected no results, got %v", r)
+	}
+
+	// Duplicates are returned in the order they were added.
+	dup := NewReverseGeocoder([]AdgangsAdresse{
+		{ID: "a", Adgangspunkt: Adgangspunkt{Koordinater: []float64{12, 55}}},
+		{ID: "b", Adgangspunkt: Adgangspunkt{Koordinater: []float64{12, 55}}},
+		{ID: "c", Adgangspunkt: Adgangspunkt{Koordinater: []float64{12, 55}}},
+	})
+	r, err := dup.Nearest(WGS84Point(12, 55), 3)
+	if err != nil || len(r) != 3 || r[0].AdgangsAdresse.ID != "a" || r[1].AdgangsAdresse.ID != "b" || r[2].AdgangsAdresse.ID != "c" || r[0].Afstand != 0 {
+		t.Fatalf("Unexpected result: %+v", r)
+	}
+}
+
+func TestReverseGeocoderETRS89(t *testing.T) {
+	// Entries with only ETRS89 koordinater are transformed.
+	g := NewReverseGeocoder([]AdgangsAdresse{
+		{ID: "a", Adgangspunkt: Adgangspunkt{Etrs89Koordinater: []float64{723743.16, 6175322.16}}},
+		{ID: "b", Adgangspunkt: Adgangspunkt{Koordinater: []float64{12.5, 55.6}}},
+	})
+	if g.Len() != 2 {
+		t.Fatalf("Expected 2 entries, got %d", g.Len())
+	}
+	for _, p := range []Point{WGS84Point(12.5582458296225, 55.6720594006065), ETRS89Point(723743, 6175322)} {
+		r, err := g.Nearest(p, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(r) != 1 || r[0].AdgangsAdresse.ID != "a" || r[0].Afstand > 1 {
+			t.Fatalf("%v: unexpected result: %+v", p, r)
+		}
+	}
+	if _, err := g.Reverse(Point{X: 1, Y: 2, SRID: 3857}); err == nil {
+		t.Fatal("Expected error for unsupported srid")
+	}
+}
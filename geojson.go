@@ -0,0 +1,240 @@
+package dawa
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Geometry types of a Geometry.
+const (
+	GeometryPoint        = "Point"
+	GeometryPolygon      = "Polygon"
+	GeometryMultiPolygon = "MultiPolygon"
+)
+
+// Geometry is a typed GeoJSON geometry.
+// The points have the SRID of the feature collection.
+type Geometry struct {
+	Type         string      // GeometryPoint, GeometryPolygon or GeometryMultiPolygon. Empty if the feature has no geometry.
+	Point        Point       // Set if Type is GeometryPoint.
+	Polygon      [][]Point   // The rings, if Type is GeometryPolygon.
+	MultiPolygon [][][]Point // The polygons, if Type is GeometryMultiPolygon.
+}
+
+// Feature is a GeoJSON feature with typed properties.
+//
+// DAWA returns the properties as flat fields, like CSV files,
+// which are mapped onto the fields of T like ImportCSV.
+type Feature[T any] struct {
+	Properties T
+	Geometry   Geometry
+}
+
+// geoJSONFeature is a feature as it is decoded.
+type geoJSONFeature struct {
+	Geometry *struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	} `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// geoJSONQuery will execute the query with format=geojson,
+// and return an iterator that will decode the features as they are read.
+// The properties are mapped onto T like CSV columns with opts, see ImportCSV.
+func geoJSONQuery[T any](ctx context.Context, q query, opts CSVOptions) (*Iter[Feature[T]], error) {
+	m, err := csvMappingOf[T]()
+	if err != nil {
+		return nil, err
+	}
+	srid := q.sridOf(Point{})
+	request := func(ctx context.Context, q query) (*Iter[Feature[T]], error) {
+		q.set(&textQuery{Name: "format", Values: []string{"geojson"}})
+		resp, err := q.RequestContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		props := func(v map[string]string) (T, error) {
+			return m.fromFlat(v, opts.Lenient)
+		}
+		iter := importGeoJSON(ctx, resp, srid, props, opts.onError())
+		iter.AddCloser(resp)
+		return iter, nil
+	}
+	if q.pageSize > 0 {
+		return pagesIter(ctx, q, func(ctx context.Context, n int) (*Iter[Feature[T]], error) {
+			return request(ctx, q.pageQuery(n))
+		})
+	}
+	return request(ctx, q.clone())
+}
+
+// importGeoJSON will import a GeoJSON feature collection from the supplied reader.
+// The features are decoded one by one, so the collection is never read into memory.
+// Points are given the SRID of the 'crs' of the collection, or srid if it isn't set before the features.
+// Features with properties that cannot be imported are passed to onError, and skipped if it returns nil.
+func importGeoJSON[T any](ctx context.Context, in io.Reader, srid SRID, props func(map[string]string) (T, error), onError func(*ImportError) error) *Iter[Feature[T]] {
+	ret := newIter[Feature[T]](ctx, 100)
+	in = bufio.NewReader(ret.reader(in))
+	stop := context.AfterFunc(ctx, func() {
+		ret.stop()
+		ret.drain()
+	})
+	go func() {
+		defer stop()
+		defer close(ret.a)
+		dec := json.NewDecoder(in)
+		dec.UseNumber()
+		ret.err = ret.closedErr(decodeFeatures(dec, srid, func(f *geoJSONFeature, srid SRID) error {
+			feat, err := typedFeature(f, srid, props)
+			if ierr, ok := err.(*ImportError); ok {
+				if err = onError(ierr); err == nil {
+					return nil
+				}
+			}
+			if err != nil {
+				return err
+			}
+			if !ret.send(feat) {
+				return ErrIteratorClosed
+			}
+			return nil
+		}))
+		if ret.err == nil {
+			ret.err = io.EOF
+		}
+	}()
+	return ret
+}
+
+// decodeFeatures will decode a feature collection and call fn with each feature.
+// A null collection has no features.
+func decodeFeatures(dec *json.Decoder, srid SRID, fn func(*geoJSONFeature, SRID) error) error {
+	t, err := dec.Token()
+	if err == io.EOF || (err == nil && t == nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if t != json.Delim('{') {
+		return fmt.Errorf("dawa: geojson: expected object, got %v", t)
+	}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch t {
+		case "crs":
+			var crs struct {
+				Properties struct {
+					Name string `json:"name"`
+				} `json:"properties"`
+			}
+			if err := dec.Decode(&crs); err != nil {
+				return err
+			}
+			s := crs.Properties.Name
+			if n, err := strconv.Atoi(s[strings.LastIndex(s, ":")+1:]); err == nil {
+				srid = SRID(n)
+			}
+		case "features":
+			if t, err := dec.Token(); err != nil || t != json.Delim('[') {
+				if err == nil {
+					err = fmt.Errorf("dawa: geojson: expected features array, got %v", t)
+				}
+				return err
+			}
+			for dec.More() {
+				var f geoJSONFeature
+				if err := dec.Decode(&f); err != nil {
+					return err
+				}
+				if err := fn(&f, srid); err != nil {
+					return err
+				}
+			}
+			if _, err := dec.Token(); err != nil {
+				return err
+			}
+		default:
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return err
+			}
+		}
+	}
+	_, err = dec.Token()
+	return err
+}
+
+// typedFeature converts the feature to a typed feature.
+func typedFeature[T any](f *geoJSONFeature, srid SRID, props func(map[string]string) (T, error)) (Feature[T], error) {
+	var ret Feature[T]
+	v := make(map[string]string, len(f.Properties))
+	for key, p := range f.Properties {
+		switch p := p.(type) {
+		case string:
+			v[key] = p
+		case json.Number:
+			v[key] = p.String()
+		case bool:
+			v[key] = strconv.FormatBool(p)
+		}
+	}
+	var err error
+	ret.Properties, err = props(v)
+	if err != nil {
+		return ret, err
+	}
+	if f.Geometry == nil {
+		return ret, nil
+	}
+	g := &ret.Geometry
+	g.Type = f.Geometry.Type
+	switch g.Type {
+	case GeometryPoint:
+		var c []float64
+		err = json.Unmarshal(f.Geometry.Coordinates, &c)
+		if err == nil && len(c) < 2 {
+			err = fmt.Errorf("dawa: geojson: point has %d coordinates", len(c))
+		}
+		if err == nil {
+			g.Point = Point{X: c[0], Y: c[1], SRID: srid}
+		}
+	case GeometryPolygon:
+		var c [][][]float64
+		err = json.Unmarshal(f.Geometry.Coordinates, &c)
+		g.Polygon = toRings(c, srid)
+	case GeometryMultiPolygon:
+		var c [][][][]float64
+		err = json.Unmarshal(f.Geometry.Coordinates, &c)
+		g.MultiPolygon = make([][][]Point, len(c))
+		for i := range c {
+			g.MultiPolygon[i] = toRings(c[i], srid)
+		}
+	default:
+		err = fmt.Errorf("dawa: geojson: unsupported geometry type %q", g.Type)
+	}
+	return ret, err
+}
+
+// toRings converts the rings of a GeoJSON polygon to points.
+func toRings(c [][][]float64, srid SRID) [][]Point {
+	rings := make([][]Point, len(c))
+	for i, ring := range c {
+		rings[i] = make([]Point, 0, len(ring))
+		for _, p := range ring {
+			if len(p) >= 2 {
+				rings[i] = append(rings[i], Point{X: p[0], Y: p[1], SRID: srid})
+			}
+		}
+	}
+	return rings
+}
@@ -0,0 +1,277 @@
+package dawa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var geojson_adresser = `{
+  "type": "FeatureCollection",
+  "crs": {"type": "name", "properties": {"name": "EPSG:4326"}},
+  "features": [
+    {
+      "type": "Feature",
+      "geometry": {"type": "Point", "coordinates": [8.53959543878291, 55.0972751504817]},
+      "properties": {
+        "id": "0a3f50b7-6545-32b8-e044-0003ba298018",
+        "status": 1,
+        "oprettet": "2000-02-05T18:09:56.000",
+        "ændret": "2000-02-16T21:58:33.000",
+        "vejkode": "0001",
+        "vejnavn": "A Hansensvej",
+        "husnr": "6",
+        "etage": null,
+        "dør": null,
+        "supplerendebynavn": "Vråby",
+        "postnr": "6792",
+        "postnrnavn": "Rømø",
+        "kommunekode": "0550",
+        "ejerlavkode": 1470852,
+        "etrs89koordinat_øst": 470620,
+        "etrs89koordinat_nord": 6105713,
+        "wgs84koordinat_bredde": 55.0972751504817,
+        "wgs84koordinat_længde": 8.53959543878291,
+        "tekstretning": 200.00,
+        "adgangsadresseid": "0a3f508c-3307-32b8-e044-0003ba298018",
+        "kvhx": "05500001___6_______"
+      }
+    },
+    {
+      "type": "Feature",
+      "geometry": {"type": "Point", "coordinates": [8.53820028085436, 55.0973148017997]},
+      "properties": {"id": "0a3f50b7-6544-32b8-e044-0003ba298018", "status": 3, "husnr": "5", "etage": "st", "dør": "tv"}
+    }
+  ]
+}`
+
+var geojson_postnumre = `{
+  "features": [
+    {
+      "type": "Feature",
+      "properties": {"nr": "9981", "navn": "Jerup", "stormodtager": false},
+      "geometry": {"type": "MultiPolygon", "coordinates": [[[[580000, 6370000], [590000, 6370000], [590000, 6380000], [580000, 6370000]]]]}
+    },
+    {
+      "type": "Feature",
+      "properties": {"nr": "9990", "navn": "Skagen"},
+      "geometry": {"type": "Polygon", "coordinates": [[[10.5, 57.7], [10.6, 57.7], [10.6, 57.8], [10.5, 57.7]]]}
+    }
+  ],
+  "type": "FeatureCollection",
+  "crs": {"type": "name", "properties": {"name": "EPSG:4326"}}
+}`
+
+func geoJSONServer(t *testing.T, body string) (*Client, *[]string) {
+	var queries []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(ts.Close)
+	return &Client{BaseURL: ts.URL}, &queries
+}
+
+func TestGeoJSONTyped(t *testing.T) {
+	c, queries := geoJSONServer(t, geojson_adresser)
+	iter, err := c.NewAdresseQuery().Postnr("6792").GeoJSONTyped()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []Feature[Adresse]
+	for f, err := range iter.All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, f)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 features, got %d", len(got))
+	}
+	if (*queries)[0] != "postnr=6792&format=geojson" {
+		t.Fatalf("Unexpected query: %s", (*queries)[0])
+	}
+	a := got[0].Properties
+	aa := a.Adgangsadresse
+	if a.ID != "0a3f50b7-6545-32b8-e044-0003ba298018" || a.Status != 1 || aa.Husnr != "6" || aa.Vejstykke.Navn != "A Hansensvej" || aa.Ejerlav.Kode != 1470852 {
+		t.Fatalf("Unexpected properties: %+v", a)
+	}
+	if a.Historik.Oprettet != MustParseTime("2000-02-05T18:09:56.000") || aa.Adgangspunkt.Tekstretning != 200 || aa.Kvh != "05500001___6" {
+		t.Fatalf("Unexpected properties: %+v", a)
+	}
+	if a.Adressebetegnelse != "A Hansensvej 6, Vråby, 6792 Rømø" {
+		t.Fatalf("Unexpected adressebetegnelse: %q", a.Adressebetegnelse)
+	}
+	if e, _ := aa.Adgangspunkt.ETRS89(); e != ETRS89Point(470620, 6105713) {
+		t.Fatalf("Unexpected ETRS89: %+v", e)
+	}
+	g := got[0].Geometry
+	if g.Type != GeometryPoint || g.Point != WGS84Point(8.53959543878291, 55.0972751504817) {
+		t.Fatalf("Unexpected geometry: %+v", g)
+	}
+	b := got[1].Properties
+	if b.Status != 3 || b.Etage != "st" || b.Dør != "tv" || !b.Historik.Oprettet.Time().IsZero() {
+		t.Fatalf("Unexpected properties: %+v", b)
+	}
+}
+
+func TestGeoJSONTypedPolygons(t *testing.T) {
+	c, _ := geoJSONServer(t, geojson_postnumre)
+	// The crs is after the features, so the SRID of the query is used.
+	q := c.NewPostnrQuery()
+	q.Add("srid", "25832")
+	iter, err := q.GeoJSONTyped()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := iter.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Properties.Nr != "9981" || f.Properties.Navn != "Jerup" {
+		t.Fatalf("Unexpected properties: %+v", f.Properties)
+	}
+	g := f.Geometry
+	if g.Type != GeometryMultiPolygon || len(g.MultiPolygon) != 1 || len(g.MultiPolygon[0][0]) != 4 || g.MultiPolygon[0][0][1] != ETRS89Point(590000, 6370000) {
+		t.Fatalf("Unexpected geometry: %+v", g)
+	}
+	if err := ValidatePolygon(g.MultiPolygon[0]...); err != nil {
+		t.Fatal(err)
+	}
+	f, err = iter.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g := f.Geometry; g.Type != GeometryPolygon || len(g.Polygon[0]) != 4 || g.Polygon[0][0].SRID != ETRS89UTM32 {
+		t.Fatalf("Unexpected geometry: %+v", g)
+	}
+	if _, err := iter.Next(); err != io.EOF {
+		t.Fatalf("Expected io.EOF, got %v", err)
+	}
+}
+
+func TestGeoJSONTypedStream(t *testing.T) {
+	// Write a large collection, and stop reading before the end.
+	var sb strings.Builder
+	sb.WriteString(`{"type":"FeatureCollection","features":[`)
+	for i := 0; i < 10000; i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		fmt.Fprintf(&sb, `{"type":"Feature","geometry":{"type":"Point","coordinates":[12,55]},"properties":{"id":"%d","status":1}}`, i)
+	}
+	sb.WriteString(`]}`)
+	c, _ := geoJSONServer(t, sb.String())
+	iter, err := c.NewAdgangsAdresseQuery().GeoJSONTyped()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		f, err := iter.Next()
+		if err != nil || f.Properties.ID != fmt.Sprint(i) || f.Geometry.Point.SRID != WGS84 {
+			t.Fatalf("Unexpected feature: %+v, %v", f, err)
+		}
+	}
+	iter.Close()
+	for {
+		if _, err := iter.Next(); err != nil {
+			if err != io.EOF && !errors.Is(err, ErrIteratorClosed) {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			break
+		}
+	}
+
+	// Pages are requested as GeoJSON.
+	c, queries := geoJSONServer(t, geojson_adresser)
+	iter, err = c.NewAdgangsAdresseQuery().Paginate(2, 3).GeoJSONTyped()
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for _, err := range iter.All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		n++
+	}
+	if n != 3 || len(*queries) != 2 || !strings.Contains((*queries)[1], "format=geojson") || !strings.Contains((*queries)[1], "side=2") {
+		t.Fatalf("Unexpected result: %d, %v", n, *queries)
+	}
+}
+
+func TestGeoJSONTypedErrors(t *testing.T) {
+	tests := map[string]string{
+		`null`: "",
+		`{"type":"FeatureCollection","features":[]}`: "",
+		`[]`:              "expected object",
+		`{"features":{}}`: "expected features array",
+		`{"features":[{"geometry":{"type":"LineString","coordinates":[]},"properties":{"status":1}}]}`: "unsupported geometry",
+		`{"features":[{"geometry":{"type":"Point","coordinates":[1]},"properties":{"status":1}}]}`:     "point has 1 coordinates",
+		`{"features":[{"properties":{"status":"x"}}]}`:                                                 "invalid syntax",
+		`{"features":[{"properties":{"status":1}}`:                                                     "unexpected end",
+		`{"features":[`:               "unexpected end",
+		`{"type":"FeatureCollection"`: "unexpected end",
+	}
+	for body, reason := range tests {
+		c, _ := geoJSONServer(t, body)
+		iter, err := c.NewAdresseQuery().GeoJSONTyped()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for {
+			_, err = iter.Next()
+			if err != nil {
+				break
+			}
+		}
+		if reason == "" {
+			if err != io.EOF {
+				t.Errorf("%s: expected io.EOF, got %v", body, err)
+			}
+			continue
+		}
+		if err == io.EOF || !strings.Contains(err.Error(), reason) {
+			t.Errorf("%s: expected error containing %q, got %v", body, reason, err)
+		}
+	}
+}
+
+func TestGeoJSONTypedOptions(t *testing.T) {
+	body := `{"features":[{"properties":{"husnr":"1","status":"x"}},{"properties":{"husnr":"2","status":1}}]}`
+	c, _ := geoJSONServer(t, body)
+	husnr := func(opts CSVOptions) ([]string, error) {
+		iter, err := c.NewAdresseQuery().GeoJSONTypedOptions(context.Background(), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for f, err := range iter.All() {
+			if err != nil {
+				return got, err
+			}
+			got = append(got, f.Properties.Adgangsadresse.Husnr)
+		}
+		return got, nil
+	}
+
+	// The default options stop at the first error.
+	var ierr *ImportError
+	if _, err := husnr(CSVOptions{}); !errors.As(err, &ierr) || ierr.Column != "status" {
+		t.Fatalf("Expected *ImportError, got %v", err)
+	}
+	got, err := husnr(CSVOptions{Lenient: true})
+	if err != nil || strings.Join(got, ",") != "1,2" {
+		t.Fatalf("Unexpected result: %v, %v", got, err)
+	}
+	var skipped ImportSummary
+	got, err = husnr(CSVOptions{OnError: skipped.Skip})
+	if err != nil || strings.Join(got, ",") != "2" || skipped.Skipped != 1 {
+		t.Fatalf("Unexpected result: %v, %v, %s", got, err, skipped.String())
+	}
+}
@@ -0,0 +1,228 @@
+package dawa
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ValidatePoint returns an error if the point is outside the valid range of its SRID.
+//
+// For WGS84 længde must be within ±180 and bredde within ±90.
+// For ETRS89/UTM32 øst must be within 0-1000000 and nord within 0-9400000 meters.
+func ValidatePoint(p Point) error {
+	x, y, s := p.X, p.Y, p.srid()
+	switch {
+	case math.IsNaN(x) || math.IsNaN(y) || math.IsInf(x, 0) || math.IsInf(y, 0):
+		return fmt.Errorf("dawa: invalid point %v", p)
+	case !s.Valid():
+		return fmt.Errorf("dawa: unsupported srid %d", s)
+	case s == WGS84 && (x < -180 || x > 180 || y < -90 || y > 90):
+		return fmt.Errorf("dawa: point %v outside WGS84 range, længde must be within ±180 and bredde within ±90", p)
+	case s == ETRS89UTM32 && (x < 0 || x > 1000000 || y < 0 || y > 9400000):
+		return fmt.Errorf("dawa: point %v outside ETRS89/UTM32 range, øst must be within 0-1000000 and nord within 0-9400000", p)
+	}
+	return nil
+}
+
+// ValidatePolygon returns an error if the polygon will be rejected by DAWA or is ambiguous.
+//
+// Each ring must have at least 4 points, and be closed, so the first and last point are identical.
+// A ring must have an area, and must not intersect itself.
+// The first ring is the outer ring, and the following rings are holes.
+// Either winding order is accepted. When the polygon is added to a query,
+// the outer ring is sent counterclockwise and holes clockwise, like GeoJSON.
+// All points must be valid for their SRID, see ValidatePoint.
+func ValidatePolygon(rings ...[]Point) error {
+	if len(rings) == 0 {
+		return errors.New("dawa: invalid polygon: no rings")
+	}
+	for i, ring := range rings {
+		if err := validateRing(ring); err != nil {
+			return fmt.Errorf("dawa: invalid polygon: ring %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// validateRing returns the reason the ring is invalid.
+func validateRing(ring []Point) error {
+	if len(ring) < 4 {
+		return fmt.Errorf("%d points, must be at least 4", len(ring))
+	}
+	for _, p := range ring {
+		if err := ValidatePoint(p); err != nil {
+			return err
+		}
+	}
+	pts := ringPoints(ring)
+	first, last := pts[0], pts[len(pts)-1]
+	if first.X != last.X || first.Y != last.Y {
+		return fmt.Errorf("not closed, first point %v and last point %v must be identical", ring[0], ring[len(ring)-1])
+	}
+	if ringArea(pts) == 0 {
+		return errors.New("has no area")
+	}
+	if ringIntersects(pts) {
+		return errors.New("intersects itself")
+	}
+	return nil
+}
+
+// ringPoints returns the points of the ring in the SRID of the first point,
+// so they can be compared.
+func ringPoints(ring []Point) []Point {
+	srid := ring[0].srid()
+	pts := make([]Point, len(ring))
+	for i, p := range ring {
+		pts[i], _ = p.Transform(srid)
+	}
+	return pts
+}
+
+// ringArea returns the signed area of a closed ring using the shoelace formula.
+// The area is positive if the ring is counterclockwise.
+func ringArea(ring []Point) float64 {
+	var a float64
+	for i := 0; i < len(ring)-1; i++ {
+		a += ring[i].X*ring[i+1].Y - ring[i+1].X*ring[i].Y
+	}
+	return a / 2
+}
+
+// windRing returns the ring counterclockwise if ccw is true, otherwise clockwise.
+// The ring is reversed if needed, otherwise it is returned as is.
+func windRing(ring []Point, ccw bool) []Point {
+	if (ringArea(ringPoints(ring)) > 0) == ccw {
+		return ring
+	}
+	ret := make([]Point, len(ring))
+	for i, p := range ring {
+		ret[len(ring)-1-i] = p
+	}
+	return ret
+}
+
+// ringIntersects returns true if two segments of the closed ring intersect,
+// other than neighbouring segments meeting at their shared point.
+func ringIntersects(ring []Point) bool {
+	// Repeated points give segments without length, which are skipped.
+	pts := make([]Point, 0, len(ring))
+	for _, p := range ring {
+		if n := len(pts); n == 0 || pts[n-1].X != p.X || pts[n-1].Y != p.Y {
+			pts = append(pts, p)
+		}
+	}
+	n := len(pts) - 1
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			a, b, c, d := pts[i], pts[i+1], pts[j], pts[j+1]
+			switch {
+			case j == i+1:
+				// The ring turns back on itself.
+				if orientation(a, b, d) == 0 && (b.X-a.X)*(d.X-b.X)+(b.Y-a.Y)*(d.Y-b.Y) < 0 {
+					return true
+				}
+			case i == 0 && j == n-1:
+				if orientation(c, d, b) == 0 && (d.X-c.X)*(b.X-d.X)+(d.Y-c.Y)*(b.Y-d.Y) < 0 {
+					return true
+				}
+			case segmentsIntersect(a, b, c, d):
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// orientation returns 1 if c is to the left of the line from a to b,
+// -1 if it is to the right and 0 if the points are collinear.
+func orientation(a, b, c Point) int {
+	v := (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+// segmentsIntersect returns true if the segments a-b and c-d have a point in common.
+func segmentsIntersect(a, b, c, d Point) bool {
+	o1, o2 := orientation(a, b, c), orientation(a, b, d)
+	o3, o4 := orientation(c, d, a), orientation(c, d, b)
+	if o1 != o2 && o3 != o4 {
+		return true
+	}
+	onSegment := func(p, q, r Point) bool {
+		return math.Min(p.X, q.X) <= r.X && r.X <= math.Max(p.X, q.X) &&
+			math.Min(p.Y, q.Y) <= r.Y && r.Y <= math.Max(p.Y, q.Y)
+	}
+	return (o1 == 0 && onSegment(a, b, c)) || (o2 == 0 && onSegment(a, b, d)) ||
+		(o3 == 0 && onSegment(c, d, a)) || (o4 == 0 && onSegment(c, d, b))
+}
+
+// ValidateCircle returns an error if the center is invalid for its SRID,
+// or if the radius isn't a positive number of meters.
+func ValidateCircle(center Point, radiusMeters float64) error {
+	if err := ValidatePoint(center); err != nil {
+		return fmt.Errorf("dawa: invalid cirkel: %v", err)
+	}
+	if !(radiusMeters > 0) || math.IsInf(radiusMeters, 0) {
+		return fmt.Errorf("dawa: invalid cirkel: radius %v must be a positive number of meters", radiusMeters)
+	}
+	return nil
+}
+
+// invalidate will add err as a warning, and make the query fail with it when executed.
+func (q *query) invalidate(err error) {
+	q.warnings = append(q.warnings, err)
+	if q.err == nil {
+		q.err = err
+	}
+}
+
+// addPolygon will add the polygon parameter with the rings.
+// If the rings are invalid, the query is invalidated.
+func (q *query) addPolygon(rings [][]Point) {
+	if err := ValidatePolygon(rings...); err != nil {
+		q.invalidate(err)
+		return
+	}
+	srid := q.geoSrid(rings[0][0])
+	b := make([]byte, 0, 64)
+	b = append(b, '[')
+	for i, ring := range rings {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = append(b, '[')
+		// The outer ring is sent counterclockwise, and holes clockwise.
+		for j, p := range windRing(ring, i == 0) {
+			if j > 0 {
+				b = append(b, ',')
+			}
+			p, _ = p.Transform(srid)
+			b = append(b, '[')
+			b = append(b, p.String()...)
+			b = append(b, ']')
+		}
+		b = append(b, ']')
+	}
+	b = append(b, ']')
+	q.add(&textQuery{Name: "polygon", Values: []string{string(b)}, Multi: false, Null: false})
+}
+
+// addCircle will add the cirkel parameter with the center and radius.
+// If the values are invalid, the query is invalidated.
+func (q *query) addCircle(center Point, radiusMeters float64) {
+	if err := ValidateCircle(center, radiusMeters); err != nil {
+		q.invalidate(err)
+		return
+	}
+	p, _ := center.Transform(q.geoSrid(center))
+	v := p.String() + "," + strconv.FormatFloat(radiusMeters, 'f', -1, 64)
+	q.add(&textQuery{Name: "cirkel", Values: []string{v}, Multi: false, Null: false})
+}
@@ -0,0 +1,133 @@
+package dawa
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func reverse(r []Point) []Point {
+	ret := make([]Point, len(r))
+	for i := range r {
+		ret[i] = r[len(r)-1-i]
+	}
+	return ret
+}
+
+func TestValidatePolygon(t *testing.T) {
+	outer := []Point{WGS84Point(10.3, 55.3), WGS84Point(10.4, 55.3), WGS84Point(10.4, 55.4), WGS84Point(10.3, 55.4), WGS84Point(10.3, 55.3)}
+	hole := []Point{WGS84Point(10.32, 55.32), WGS84Point(10.32, 55.38), WGS84Point(10.38, 55.38), WGS84Point(10.38, 55.32), WGS84Point(10.32, 55.32)}
+	if err := ValidatePolygon(outer, hole); err != nil {
+		t.Fatal(err)
+	}
+	// Rings in different SRIDs are compared after transformation.
+	e := make([]Point, len(outer))
+	for i, p := range outer {
+		e[i], _ = p.Transform(ETRS89UTM32)
+	}
+	if err := ValidatePolygon(e, hole); err != nil {
+		t.Fatal(err)
+	}
+
+	// Either winding order is accepted.
+	if err := ValidatePolygon(reverse(outer), reverse(hole)); err != nil {
+		t.Fatal(err)
+	}
+	// Repeated points are allowed.
+	if err := ValidatePolygon(append([]Point{outer[0]}, outer...)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		rings  [][]Point
+		reason string
+	}{
+		{nil, "no rings"},
+		{[][]Point{outer[:3]}, "ring 0: 3 points"},
+		{[][]Point{outer[:4]}, "ring 0: not closed"},
+		{[][]Point{{WGS84Point(10.3, 55.3), WGS84Point(10.4, 55.4), WGS84Point(10.4, 55.3), WGS84Point(10.3, 55.45), WGS84Point(10.3, 55.3)}}, "ring 0: intersects itself"},
+		{[][]Point{outer, {WGS84Point(10.32, 55.32), WGS84Point(10.38, 55.32), WGS84Point(10.38, 55.38), WGS84Point(10.35, 55.38), WGS84Point(10.35, 55.30), WGS84Point(10.32, 55.32)}}, "ring 1: intersects itself"},
+		{[][]Point{{WGS84Point(10.3, 55.3), WGS84Point(10.4, 55.3), WGS84Point(10.4, 55.4), WGS84Point(10.4, 55.35), WGS84Point(10.3, 55.3)}}, "ring 0: intersects itself"},
+		{[][]Point{{WGS84Point(10, 55), WGS84Point(11, 55), WGS84Point(12, 55), WGS84Point(10, 55)}}, "ring 0: has no area"},
+		{[][]Point{{WGS84Point(55.3, 10.3), WGS84Point(55.3, 190), WGS84Point(55.4, 10.4), WGS84Point(55.3, 10.3)}}, "outside WGS84 range"},
+		{[][]Point{{ETRS89Point(-1, 6175300), ETRS89Point(723800, 6175300), ETRS89Point(723800, 6175400), ETRS89Point(-1, 6175300)}}, "outside ETRS89/UTM32 range"},
+		{[][]Point{{WGS84Point(math.NaN(), 55), WGS84Point(11, 55), WGS84Point(11, 56), WGS84Point(math.NaN(), 55)}}, "invalid point"},
+		{[][]Point{{{X: 1, Y: 2, SRID: 3857}, {X: 2, Y: 2, SRID: 3857}, {X: 2, Y: 3, SRID: 3857}, {X: 1, Y: 2, SRID: 3857}}}, "unsupported srid 3857"},
+	}
+	for _, test := range tests {
+		err := ValidatePolygon(test.rings...)
+		if err == nil || !strings.Contains(err.Error(), test.reason) || !strings.HasPrefix(err.Error(), "dawa: invalid polygon") {
+			t.Errorf("Expected error containing %q, got %v", test.reason, err)
+		}
+	}
+}
+
+func TestValidateCircle(t *testing.T) {
+	if err := ValidateCircle(ETRS89Point(723743.16, 6175322.16), 100); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		center Point
+		radius float64
+		reason string
+	}{
+		{WGS84Point(12, 55), 0, "radius 0"},
+		{WGS84Point(12, 55), -5, "radius -5"},
+		{WGS84Point(12, 55), math.NaN(), "radius NaN"},
+		{WGS84Point(12, 55), math.Inf(1), "radius +Inf"},
+		{WGS84Point(12, 95), 10, "outside WGS84 range"},
+	}
+	for _, test := range tests {
+		err := ValidateCircle(test.center, test.radius)
+		if err == nil || !strings.Contains(err.Error(), test.reason) || !strings.HasPrefix(err.Error(), "dawa: invalid cirkel") {
+			t.Errorf("Expected error containing %q, got %v", test.reason, err)
+		}
+	}
+}
+
+func TestQueryInvalidGeometry(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("[]"))
+	}))
+	defer ts.Close()
+	c := &Client{BaseURL: ts.URL}
+
+	open := []Point{WGS84Point(10.3, 55.3), WGS84Point(10.4, 55.3), WGS84Point(10.4, 55.31), WGS84Point(10.3, 55.31)}
+	q := c.NewAdresseQuery().Polygon(open).Vejnavn("Rødkildevej")
+	if !q.HasWarnings() || strings.Contains(q.URL(), "polygon") {
+		t.Fatalf("Expected warning and no polygon, got %v, %s", q.Warnings(), q.URL())
+	}
+	if _, err := q.All(); err == nil || !strings.Contains(err.Error(), "not closed") {
+		t.Fatalf("Expected error, got %v", err)
+	}
+	aq := c.NewAdgangsAdresseQuery().Circle(WGS84Point(12, 55), -1)
+	if _, err := aq.First(); err == nil || !strings.Contains(err.Error(), "radius") {
+		t.Fatalf("Expected error, got %v", err)
+	}
+	if _, err := aq.GeoJSON(); err == nil || !strings.Contains(err.Error(), "radius") {
+		t.Fatalf("Expected error, got %v", err)
+	}
+	if requests != 0 {
+		t.Fatalf("Expected no requests, got %d", requests)
+	}
+
+	// The outer ring is sent counterclockwise and holes clockwise.
+	outer := []Point{WGS84Point(10.3, 55.3), WGS84Point(10.4, 55.3), WGS84Point(10.4, 55.4), WGS84Point(10.3, 55.4), WGS84Point(10.3, 55.3)}
+	hole := []Point{WGS84Point(10.32, 55.32), WGS84Point(10.32, 55.38), WGS84Point(10.38, 55.38), WGS84Point(10.38, 55.32), WGS84Point(10.32, 55.32)}
+	want := c.NewAdresseQuery().Polygon(outer, hole).URL()
+	if got := c.NewAdresseQuery().Polygon(reverse(outer), reverse(hole)).URL(); got != want || !strings.Contains(got, "polygon") {
+		t.Fatalf("Expected %s, got %s", want, got)
+	}
+
+	// Valid geometry is sent.
+	if _, err := c.NewAdgangsAdresseQuery().Cirkel(WGS84Point(12, 55), 10).All(); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 {
+		t.Fatalf("Expected 1 request, got %d", requests)
+	}
+}
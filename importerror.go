@@ -0,0 +1,82 @@
+package dawa
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ImportError is the error of a row that could not be imported.
+//
+// It is returned by the CSV importers, or passed to CSVOptions.OnError.
+type ImportError struct {
+	Line   int    // The line where the row starts. The header is line 1. Zero if the input has no lines, like GeoJSON properties.
+	Column string // The column name. Empty if the row could not be read, for instance if it has the wrong number of columns.
+	Value  string // The raw value of the column.
+	Err    error  // The cause.
+}
+
+func (e *ImportError) Error() string {
+	var b strings.Builder
+	b.WriteString("dawa: ")
+	if e.Line > 0 {
+		fmt.Fprintf(&b, "line %d: ", e.Line)
+	}
+	if e.Column != "" {
+		fmt.Fprintf(&b, "column %s: ", e.Column)
+	}
+	b.WriteString(e.Err.Error())
+	return b.String()
+}
+
+// Unwrap returns the cause.
+func (e *ImportError) Unwrap() error {
+	return e.Err
+}
+
+// ImportSummary collects the rows that have been skipped by an import.
+//
+// Use the Skip method as CSVOptions.OnError. The summary is updated while the rows are read,
+// so it should not be read before the iterator has returned io.EOF.
+//
+// Example:
+//			var skipped dawa.ImportSummary
+//			iter, err := dawa.ImportCSV[dawa.Adresse](file, dawa.CSVOptions{OnError: skipped.Skip})
+//			// ... read all entries ...
+//			fmt.Println(skipped.String())
+type ImportSummary struct {
+	Skipped   int            // The number of skipped rows.
+	Columns   map[string]int // The number of skipped rows by column name. Rows that could not be read have an empty column name.
+	Errors    []*ImportError // The errors of the skipped rows, up to MaxErrors.
+	MaxErrors int            // The maximum number of errors kept in Errors. If 0, all errors are kept.
+}
+
+// Skip will record the error and skip the row.
+// It can be used as CSVOptions.OnError.
+func (s *ImportSummary) Skip(err *ImportError) error {
+	s.Skipped++
+	if s.Columns == nil {
+		s.Columns = make(map[string]int)
+	}
+	s.Columns[err.Column]++
+	if s.MaxErrors <= 0 || len(s.Errors) < s.MaxErrors {
+		s.Errors = append(s.Errors, err)
+	}
+	return nil
+}
+
+// String returns the number of skipped rows and the errors.
+func (s *ImportSummary) String() string {
+	if s.Skipped == 0 {
+		return "no rows skipped"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d rows skipped", s.Skipped)
+	for _, err := range s.Errors {
+		b.WriteString("\n")
+		b.WriteString(err.Error())
+	}
+	if n := s.Skipped - len(s.Errors); n > 0 {
+		fmt.Fprintf(&b, "\n... and %d more", n)
+	}
+	return b.String()
+}
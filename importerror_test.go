@@ -0,0 +1,126 @@
+package dawa
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestImportError(t *testing.T) {
+	// Row 2 on line 3 has a bad timestamp.
+	data := strings.Replace(csv_data, "2000-02-05T18:09:53.000", "igår", 1)
+	iter, err := ImportAdresserCSV(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := iter.Next(); err != nil {
+		t.Fatal(err)
+	}
+	_, err = iter.Next()
+	var ierr *ImportError
+	if !errors.As(err, &ierr) {
+		t.Fatalf("Expected *ImportError, got %T: %v", err, err)
+	}
+	if ierr.Line != 3 || ierr.Column != "oprettet" || ierr.Value != "igår" || ierr.Err == nil {
+		t.Fatalf("Unexpected error: %+v", ierr)
+	}
+	if !strings.HasPrefix(err.Error(), "dawa: line 3: column oprettet: parsing time") {
+		t.Fatalf("Unexpected message: %v", err)
+	}
+
+	// Multi-line values are counted.
+	iter2, err := ImportCSV[Ejerlav](strings.NewReader("navn,kode\n\"Kirkeby,\nRømø\",1\nx,y\n"), CSVOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	iter2.Next()
+	if _, err := iter2.Next(); !errors.As(err, &ierr) || ierr.Line != 4 || ierr.Column != "kode" || ierr.Value != "y" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Errors without a line.
+	err = (&ImportError{Column: "status", Err: io.ErrUnexpectedEOF})
+	if err.Error() != "dawa: column status: unexpected EOF" || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestImportSkipErrors(t *testing.T) {
+	data := strings.Replace(csv_data, "2000-02-05T18:09:53.000", "igår", 1)
+	// A row with too few columns.
+	data = strings.Replace(data, "\n0a3f50b7-6547", "\n1,2,3\n0a3f50b7-6547", 1)
+	// A bad coordinate in the last row.
+	data = strings.Replace(data, "470587,6105811", "470587,nord", 1)
+	data += strings.SplitN(csv_data, "\n", 3)[1] + "\n"
+
+	var skipped ImportSummary
+	iter, err := ImportCSV[Adresse](strings.NewReader(data), CSVOptions{OnError: skipped.Skip})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for a, err := range iter.All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, a.ID)
+	}
+	if len(ids) != 2 || ids[0] != "0a3f50b7-6545-32b8-e044-0003ba298018" || ids[1] != ids[0] {
+		t.Fatalf("Unexpected rows: %v", ids)
+	}
+	if skipped.Skipped != 3 || len(skipped.Errors) != 3 {
+		t.Fatalf("Unexpected summary: %+v", skipped)
+	}
+	if skipped.Columns["oprettet"] != 1 || skipped.Columns[""] != 1 || skipped.Columns["etrs89koordinat_nord"] != 1 {
+		t.Fatalf("Unexpected columns: %v", skipped.Columns)
+	}
+	lines := []int{3, 4, 5}
+	for i, err := range skipped.Errors {
+		if err.Line != lines[i] {
+			t.Errorf("Error %d: expected line %d, got %d", i, lines[i], err.Line)
+		}
+	}
+	if !errors.Is(skipped.Errors[1], csv.ErrFieldCount) {
+		t.Errorf("Expected csv.ErrFieldCount, got %v", skipped.Errors[1])
+	}
+	s := skipped.String()
+	if !strings.HasPrefix(s, "3 rows skipped\ndawa: line 3: column oprettet") || !strings.Contains(s, "line 5: column etrs89koordinat_nord") {
+		t.Fatalf("Unexpected summary:\n%s", s)
+	}
+
+	// Only MaxErrors are kept.
+	skipped = ImportSummary{MaxErrors: 1}
+	iter, _ = ImportCSV[Adresse](strings.NewReader(data), CSVOptions{OnError: skipped.Skip})
+	for range iter.All() {
+	}
+	if skipped.Skipped != 3 || len(skipped.Errors) != 1 || !strings.HasSuffix(skipped.String(), "\n... and 2 more") {
+		t.Fatalf("Unexpected summary: %s", skipped.String())
+	}
+	if (&ImportSummary{}).String() != "no rows skipped" {
+		t.Fatal("Unexpected empty summary")
+	}
+
+	// OnError can stop the import.
+	stop := errors.New("stop")
+	var n int
+	iter, _ = ImportCSV[Adresse](strings.NewReader(data), CSVOptions{OnError: func(err *ImportError) error {
+		if err.Column == "" {
+			return stop
+		}
+		return nil
+	}})
+	for _, err := range iter.All() {
+		if err != nil {
+			if err != stop {
+				t.Fatalf("Expected stop, got %v", err)
+			}
+			break
+		}
+		n++
+	}
+	if n != 1 {
+		t.Fatalf("Expected 1 row before stopping, got %d", n)
+	}
+}
@@ -0,0 +1,635 @@
+package dawa
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Index is an in-memory index of adresser and adgangsadresser.
+//
+// It can be loaded from the bulk downloads, and queried offline
+// using queries with the same methods as the query builders.
+//
+// An Index is safe for concurrent use.
+//
+// Example:
+//			idx := dawa.NewIndex()
+//			iter, _ := dawa.ImportAdresserCSV(f)
+//			err := idx.LoadAdresser(iter)
+//
+//			// Find all addresses on a road
+//			all, err := idx.NewAdresseQuery().Postnr("9000").Vejnavn("Danmarksgade").All()
+type Index struct {
+	adresser        *index[Adresse]
+	adgangsadresser *index[AdgangsAdresse]
+}
+
+// NewIndex returns a new empty index.
+func NewIndex() *Index {
+	return &Index{
+		adresser:        newIndex(adresseFields, indexKeys),
+		adgangsadresser: newIndex(adgangsAdresseFields, indexKeys),
+	}
+}
+
+// indexKeys are the combinations of parameters that are indexed.
+// Queries that specify all parameters of a key will use the index,
+// other queries will scan all entries.
+var indexKeys = [][]string{
+	{"id"},
+	{"kvhx"},
+	{"kvh"},
+	{"postnr", "vejnavn"},
+	{"kommunekode", "vejkode"},
+	{"ejerlavkode", "matrikelnr"},
+}
+
+var adresseFields = map[string]func(a *Adresse) string{
+	"id":                   func(a *Adresse) string { return a.ID },
+	"adgangsadresseid":     func(a *Adresse) string { return a.Adgangsadresse.ID },
+	"etage":                func(a *Adresse) string { return a.Etage },
+	"dør":                  func(a *Adresse) string { return a.Dør },
+	"kvhx":                 func(a *Adresse) string { return a.Kvhx },
+	"status":               func(a *Adresse) string { return strconv.Itoa(a.Status) },
+	"vejkode":              func(a *Adresse) string { return a.Adgangsadresse.Vejstykke.Kode },
+	"vejnavn":              func(a *Adresse) string { return a.Adgangsadresse.Vejstykke.Navn },
+	"husnr":                func(a *Adresse) string { return a.Adgangsadresse.Husnr },
+	"supplerendebynavn":    func(a *Adresse) string { return a.Adgangsadresse.SupplerendeBynavn },
+	"postnr":               func(a *Adresse) string { return a.Adgangsadresse.Postnummer.Nr },
+	"kommunekode":          func(a *Adresse) string { return a.Adgangsadresse.Kommune.Kode },
+	"ejerlavkode":          func(a *Adresse) string { return strconv.Itoa(a.Adgangsadresse.Ejerlav.Kode) },
+	"matrikelnr":           func(a *Adresse) string { return a.Adgangsadresse.Matrikelnr },
+	"esrejendomsnr":        func(a *Adresse) string { return a.Adgangsadresse.EsrEjendomsNr },
+	"regionskode":          func(a *Adresse) string { return a.Adgangsadresse.Region.Kode },
+	"sognekode":            func(a *Adresse) string { return a.Adgangsadresse.Sogn.Kode },
+	"opstillingskredskode": func(a *Adresse) string { return a.Adgangsadresse.Opstillingskreds.Kode },
+	"retskredskode":        func(a *Adresse) string { return a.Adgangsadresse.Retskreds.Kode },
+	"politikredskode":      func(a *Adresse) string { return a.Adgangsadresse.Politikreds.Kode },
+}
+
+var adgangsAdresseFields = map[string]func(a *AdgangsAdresse) string{
+	"id":                   func(a *AdgangsAdresse) string { return a.ID },
+	"kvh":                  func(a *AdgangsAdresse) string { return a.Kvh },
+	"status":               func(a *AdgangsAdresse) string { return strconv.Itoa(a.Status) },
+	"vejkode":              func(a *AdgangsAdresse) string { return a.Vejstykke.Kode },
+	"vejnavn":              func(a *AdgangsAdresse) string { return a.Vejstykke.Navn },
+	"husnr":                func(a *AdgangsAdresse) string { return a.Husnr },
+	"supplerendebynavn":    func(a *AdgangsAdresse) string { return a.SupplerendeBynavn },
+	"postnr":               func(a *AdgangsAdresse) string { return a.Postnummer.Nr },
+	"kommunekode":          func(a *AdgangsAdresse) string { return a.Kommune.Kode },
+	"ejerlavkode":          func(a *AdgangsAdresse) string { return strconv.Itoa(a.Ejerlav.Kode) },
+	"matrikelnr":           func(a *AdgangsAdresse) string { return a.Matrikelnr },
+	"esrejendomsnr":        func(a *AdgangsAdresse) string { return a.EsrEjendomsNr },
+	"regionskode":          func(a *AdgangsAdresse) string { return a.Region.Kode },
+	"sognekode":            func(a *AdgangsAdresse) string { return a.Sogn.Kode },
+	"opstillingskredskode": func(a *AdgangsAdresse) string { return a.Opstillingskreds.Kode },
+	"retskredskode":        func(a *AdgangsAdresse) string { return a.Retskreds.Kode },
+	"politikredskode":      func(a *AdgangsAdresse) string { return a.Politikreds.Kode },
+}
+
+// AddAdresser will add the addresses to the index.
+func (x *Index) AddAdresser(a ...Adresse) {
+	x.adresser.add(a...)
+}
+
+// AddAdgangsAdresser will add the addresses to the index.
+func (x *Index) AddAdgangsAdresser(a ...AdgangsAdresse) {
+	x.adgangsadresser.add(a...)
+}
+
+// LoadAdresser will add all addresses from the iterator to the index.
+// The iterator can be returned by ImportAdresserCSV, ImportAdresserJSON or a query.
+// The iterator is closed when the function returns.
+func (x *Index) LoadAdresser(iter *AdresseIter) error {
+	return loadIndex(x.adresser, iter)
+}
+
+// LoadAdgangsAdresser will add all addresses from the iterator to the index.
+// The iterator can be returned by ImportAdgangsAdresserCSV, ImportAdgangsAdresserJSON or a query.
+// The iterator is closed when the function returns.
+func (x *Index) LoadAdgangsAdresser(iter *AdgangsAdresseIter) error {
+	return loadIndex(x.adgangsadresser, iter)
+}
+
+// GetAdresseID will return a single Adresse with the specified ID.
+// Will return (nil, io.EOF) if there is no results.
+func (x *Index) GetAdresseID(id string) (*Adresse, error) {
+	return x.NewAdresseQuery().ID(id).First()
+}
+
+// GetAAID will return a single AdgangsAdresse with the specified ID.
+// Will return (nil, io.EOF) if there is no results.
+func (x *Index) GetAAID(id string) (*AdgangsAdresse, error) {
+	return x.NewAdgangsAdresseQuery().ID(id).First()
+}
+
+// loadIndex will add all entries from iter to idx.
+func loadIndex[T any](idx *index[T], iter *Iter[T]) error {
+	defer iter.Close()
+	const batch = 1000
+	items := make([]T, 0, batch)
+	for {
+		a, err := iter.Next()
+		if err == io.EOF {
+			idx.add(items...)
+			return nil
+		}
+		if err != nil {
+			idx.add(items...)
+			return err
+		}
+		items = append(items, *a)
+		if len(items) == batch {
+			idx.add(items...)
+			items = items[:0]
+		}
+	}
+}
+
+// index contains entries of T with keys for fast lookup.
+type index[T any] struct {
+	mu     sync.RWMutex
+	items  []T
+	fields map[string]func(*T) string
+	keys   [][]string
+	lookup []map[string][]int // one map per key
+}
+
+func newIndex[T any](fields map[string]func(*T) string, keys [][]string) *index[T] {
+	idx := &index[T]{fields: fields}
+	for _, k := range keys {
+		// Only use keys for fields this type has.
+		valid := true
+		for _, name := range k {
+			if _, ok := fields[name]; !ok {
+				valid = false
+			}
+		}
+		if valid {
+			idx.keys = append(idx.keys, k)
+			idx.lookup = append(idx.lookup, make(map[string][]int))
+		}
+	}
+	return idx
+}
+
+// key returns the lookup key for the values of the fields.
+func indexKey(values []string) string {
+	return strings.Join(values, "\x00")
+}
+
+func (idx *index[T]) add(items ...T) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	values := make([]string, 0, 2)
+	for _, item := range items {
+		n := len(idx.items)
+		idx.items = append(idx.items, item)
+		v := &idx.items[n]
+		for i, k := range idx.keys {
+			values = values[:0]
+			for _, name := range k {
+				values = append(values, idx.fields[name](v))
+			}
+			key := indexKey(values)
+			idx.lookup[i][key] = append(idx.lookup[i][key], n)
+		}
+	}
+}
+
+// candidates returns the positions of entries that may match the parameters.
+// If no key can be used, nil, false is returned.
+func (idx *index[T]) candidates(params map[string][]string) ([]int, bool) {
+	for i, k := range idx.keys {
+		combos := [][]string{nil}
+		for _, name := range k {
+			values, ok := params[name]
+			if !ok {
+				combos = nil
+				break
+			}
+			var next [][]string
+			for _, c := range combos {
+				for _, v := range values {
+					next = append(next, append(append([]string(nil), c...), v))
+				}
+			}
+			combos = next
+		}
+		if combos == nil {
+			continue
+		}
+		var ret []int
+		for _, c := range combos {
+			ret = append(ret, idx.lookup[i][indexKey(c)]...)
+		}
+		if len(combos) > 1 {
+			sort.Ints(ret)
+			ret = dedupInts(ret)
+		}
+		return ret, true
+	}
+	return nil, false
+}
+
+func dedupInts(a []int) []int {
+	if len(a) == 0 {
+		return a
+	}
+	j := 0
+	for i := 1; i < len(a); i++ {
+		if a[i] != a[j] {
+			j++
+			a[j] = a[i]
+		}
+	}
+	return a[:j+1]
+}
+
+// match returns true if the entry matches all parameters.
+func (idx *index[T]) match(v *T, params map[string][]string) bool {
+	for name, values := range params {
+		got := idx.fields[name](v)
+		found := false
+		for _, want := range values {
+			if got == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// find returns entries matching the parameters.
+// If limit > 0, at most limit entries are returned.
+func (idx *index[T]) find(params map[string][]string, limit int) []T {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	ret := make([]T, 0)
+	check := func(n int) bool {
+		if idx.match(&idx.items[n], params) {
+			ret = append(ret, idx.items[n])
+		}
+		return limit <= 0 || len(ret) < limit
+	}
+	if c, ok := idx.candidates(params); ok {
+		for _, n := range c {
+			if !check(n) {
+				break
+			}
+		}
+		return ret
+	}
+	for n := range idx.items {
+		if !check(n) {
+			break
+		}
+	}
+	return ret
+}
+
+// indexQuery is a query against an index.
+type indexQuery[T any] struct {
+	idx    *index[T]
+	params map[string][]string
+}
+
+// add will add values for the parameter.
+// If no values are given, entries where the field is empty will match.
+func (q *indexQuery[T]) add(name string, values ...string) {
+	if q.params == nil {
+		q.params = make(map[string][]string)
+	}
+	if len(values) == 0 {
+		values = []string{""}
+	}
+	q.params[name] = append(q.params[name], values...)
+}
+
+// set will replace the values for the parameter.
+func (q *indexQuery[T]) set(name string, value string) {
+	if q.params == nil {
+		q.params = make(map[string][]string)
+	}
+	q.params[name] = []string{value}
+}
+
+// IndexAdresseQuery is a query for adresser in an Index.
+// Use (*Index).NewAdresseQuery() to get an initialized object.
+//
+// Parameters with several values will match any of the values.
+// Different parameters must all match.
+type IndexAdresseQuery struct {
+	indexQuery[Adresse]
+}
+
+// NewAdresseQuery returns a new query for adresser in the index.
+func (x *Index) NewAdresseQuery() *IndexAdresseQuery {
+	return &IndexAdresseQuery{indexQuery: indexQuery[Adresse]{idx: x.adresser}}
+}
+
+// All returns all results as an array.
+func (q IndexAdresseQuery) All() ([]Adresse, error) {
+	return q.idx.find(q.params, 0), nil
+}
+
+// First will return the first result from a query.
+// Will return (nil, io.EOF) if there is no results.
+func (q IndexAdresseQuery) First() (*Adresse, error) {
+	return indexFirst(q.idx.find(q.params, 1))
+}
+
+// Iter will return an iterator with the results.
+func (q IndexAdresseQuery) Iter() (*AdresseIter, error) {
+	return q.IterContext(context.Background())
+}
+
+// IterContext will return an iterator with the results.
+// The context is checked when reading the results.
+func (q IndexAdresseQuery) IterContext(ctx context.Context) (*AdresseIter, error) {
+	return indexIter(ctx, q.idx.find(q.params, 0)), nil
+}
+
+// ID will add a parameter for 'id' to the IndexAdresseQuery.
+func (q *IndexAdresseQuery) ID(s ...string) *IndexAdresseQuery {
+	q.add("id", s...)
+	return q
+}
+
+// AdgangsadresseID will add a parameter for 'adgangsadresseid' to the IndexAdresseQuery.
+func (q *IndexAdresseQuery) AdgangsadresseID(s ...string) *IndexAdresseQuery {
+	q.add("adgangsadresseid", s...)
+	return q
+}
+
+// Etage will add a parameter for 'etage' to the IndexAdresseQuery.
+// If no values are given, addresses without etage will match.
+func (q *IndexAdresseQuery) Etage(s ...string) *IndexAdresseQuery {
+	q.add("etage", s...)
+	return q
+}
+
+// Dør will add a parameter for 'dør' to the IndexAdresseQuery.
+// If no values are given, addresses without dør will match.
+func (q *IndexAdresseQuery) Dør(s ...string) *IndexAdresseQuery {
+	q.add("dør", s...)
+	return q
+}
+
+// Kvhx will add a parameter for 'kvhx' to the IndexAdresseQuery.
+func (q *IndexAdresseQuery) Kvhx(s string) *IndexAdresseQuery {
+	q.set("kvhx", s)
+	return q
+}
+
+// Status will add a parameter for 'status' to the IndexAdresseQuery.
+func (q *IndexAdresseQuery) Status(i int) *IndexAdresseQuery {
+	q.set("status", strconv.Itoa(i))
+	return q
+}
+
+// Vejkode will add a parameter for 'vejkode' to the IndexAdresseQuery.
+func (q *IndexAdresseQuery) Vejkode(s ...string) *IndexAdresseQuery {
+	q.add("vejkode", s...)
+	return q
+}
+
+// Vejnavn will add a parameter for 'vejnavn' to the IndexAdresseQuery.
+func (q *IndexAdresseQuery) Vejnavn(s ...string) *IndexAdresseQuery {
+	q.add("vejnavn", s...)
+	return q
+}
+
+// Husnr will add a parameter for 'husnr' to the IndexAdresseQuery.
+func (q *IndexAdresseQuery) Husnr(s ...string) *IndexAdresseQuery {
+	q.add("husnr", s...)
+	return q
+}
+
+// SupplerendeBynavn will add a parameter for 'supplerendebynavn' to the IndexAdresseQuery.
+// If no values are given, addresses without supplerende bynavn will match.
+func (q *IndexAdresseQuery) SupplerendeBynavn(s ...string) *IndexAdresseQuery {
+	q.add("supplerendebynavn", s...)
+	return q
+}
+
+// Postnr will add a parameter for 'postnr' to the IndexAdresseQuery.
+func (q *IndexAdresseQuery) Postnr(s ...string) *IndexAdresseQuery {
+	q.add("postnr", s...)
+	return q
+}
+
+// Kommunekode will add a parameter for 'kommunekode' to the IndexAdresseQuery.
+func (q *IndexAdresseQuery) Kommunekode(s ...string) *IndexAdresseQuery {
+	q.add("kommunekode", s...)
+	return q
+}
+
+// Ejerlavkode will add a parameter for 'ejerlavkode' to the IndexAdresseQuery.
+func (q *IndexAdresseQuery) Ejerlavkode(s ...string) *IndexAdresseQuery {
+	q.add("ejerlavkode", s...)
+	return q
+}
+
+// Matrikelnr will add a parameter for 'matrikelnr' to the IndexAdresseQuery.
+func (q *IndexAdresseQuery) Matrikelnr(s ...string) *IndexAdresseQuery {
+	q.add("matrikelnr", s...)
+	return q
+}
+
+// Esrejendomsnr will add a parameter for 'esrejendomsnr' to the IndexAdresseQuery.
+func (q *IndexAdresseQuery) Esrejendomsnr(s ...string) *IndexAdresseQuery {
+	q.add("esrejendomsnr", s...)
+	return q
+}
+
+// Regionskode will add a parameter for 'regionskode' to the IndexAdresseQuery.
+func (q *IndexAdresseQuery) Regionskode(s ...string) *IndexAdresseQuery {
+	q.add("regionskode", s...)
+	return q
+}
+
+// Sognekode will add a parameter for 'sognekode' to the IndexAdresseQuery.
+func (q *IndexAdresseQuery) Sognekode(s ...string) *IndexAdresseQuery {
+	q.add("sognekode", s...)
+	return q
+}
+
+// Opstillingskredskode will add a parameter for 'opstillingskredskode' to the IndexAdresseQuery.
+func (q *IndexAdresseQuery) Opstillingskredskode(s ...string) *IndexAdresseQuery {
+	q.add("opstillingskredskode", s...)
+	return q
+}
+
+// Retskredskode will add a parameter for 'retskredskode' to the IndexAdresseQuery.
+func (q *IndexAdresseQuery) Retskredskode(s ...string) *IndexAdresseQuery {
+	q.add("retskredskode", s...)
+	return q
+}
+
+// Politikredskode will add a parameter for 'politikredskode' to the IndexAdresseQuery.
+func (q *IndexAdresseQuery) Politikredskode(s ...string) *IndexAdresseQuery {
+	q.add("politikredskode", s...)
+	return q
+}
+
+// IndexAdgangsAdresseQuery is a query for adgangsadresser in an Index.
+// Use (*Index).NewAdgangsAdresseQuery() to get an initialized object.
+//
+// Parameters with several values will match any of the values.
+// Different parameters must all match.
+type IndexAdgangsAdresseQuery struct {
+	indexQuery[AdgangsAdresse]
+}
+
+// NewAdgangsAdresseQuery returns a new query for adgangsadresser in the index.
+func (x *Index) NewAdgangsAdresseQuery() *IndexAdgangsAdresseQuery {
+	return &IndexAdgangsAdresseQuery{indexQuery: indexQuery[AdgangsAdresse]{idx: x.adgangsadresser}}
+}
+
+// All returns all results as an array.
+func (q IndexAdgangsAdresseQuery) All() ([]AdgangsAdresse, error) {
+	return q.idx.find(q.params, 0), nil
+}
+
+// First will return the first result from a query.
+// Will return (nil, io.EOF) if there is no results.
+func (q IndexAdgangsAdresseQuery) First() (*AdgangsAdresse, error) {
+	return indexFirst(q.idx.find(q.params, 1))
+}
+
+// Iter will return an iterator with the results.
+func (q IndexAdgangsAdresseQuery) Iter() (*AdgangsAdresseIter, error) {
+	return q.IterContext(context.Background())
+}
+
+// IterContext will return an iterator with the results.
+// The context is checked when reading the results.
+func (q IndexAdgangsAdresseQuery) IterContext(ctx context.Context) (*AdgangsAdresseIter, error) {
+	return indexIter(ctx, q.idx.find(q.params, 0)), nil
+}
+
+// ID will add a parameter for 'id' to the IndexAdgangsAdresseQuery.
+func (q *IndexAdgangsAdresseQuery) ID(s ...string) *IndexAdgangsAdresseQuery {
+	q.add("id", s...)
+	return q
+}
+
+// Kvh will add a parameter for 'kvh' to the IndexAdgangsAdresseQuery.
+func (q *IndexAdgangsAdresseQuery) Kvh(s string) *IndexAdgangsAdresseQuery {
+	q.set("kvh", s)
+	return q
+}
+
+// Status will add a parameter for 'status' to the IndexAdgangsAdresseQuery.
+func (q *IndexAdgangsAdresseQuery) Status(i int) *IndexAdgangsAdresseQuery {
+	q.set("status", strconv.Itoa(i))
+	return q
+}
+
+// Vejkode will add a parameter for 'vejkode' to the IndexAdgangsAdresseQuery.
+func (q *IndexAdgangsAdresseQuery) Vejkode(s ...string) *IndexAdgangsAdresseQuery {
+	q.add("vejkode", s...)
+	return q
+}
+
+// Vejnavn will add a parameter for 'vejnavn' to the IndexAdgangsAdresseQuery.
+func (q *IndexAdgangsAdresseQuery) Vejnavn(s ...string) *IndexAdgangsAdresseQuery {
+	q.add("vejnavn", s...)
+	return q
+}
+
+// Husnr will add a parameter for 'husnr' to the IndexAdgangsAdresseQuery.
+func (q *IndexAdgangsAdresseQuery) Husnr(s ...string) *IndexAdgangsAdresseQuery {
+	q.add("husnr", s...)
+	return q
+}
+
+// SupplerendeBynavn will add a parameter for 'supplerendebynavn' to the IndexAdgangsAdresseQuery.
+// If no values are given, addresses without supplerende bynavn will match.
+func (q *IndexAdgangsAdresseQuery) SupplerendeBynavn(s ...string) *IndexAdgangsAdresseQuery {
+	q.add("supplerendebynavn", s...)
+	return q
+}
+
+// Postnr will add a parameter for 'postnr' to the IndexAdgangsAdresseQuery.
+func (q *IndexAdgangsAdresseQuery) Postnr(s ...string) *IndexAdgangsAdresseQuery {
+	q.add("postnr", s...)
+	return q
+}
+
+// Kommunekode will add a parameter for 'kommunekode' to the IndexAdgangsAdresseQuery.
+func (q *IndexAdgangsAdresseQuery) Kommunekode(s ...string) *IndexAdgangsAdresseQuery {
+	q.add("kommunekode", s...)
+	return q
+}
+
+// Ejerlavkode will add a parameter for 'ejerlavkode' to the IndexAdgangsAdresseQuery.
+func (q *IndexAdgangsAdresseQuery) Ejerlavkode(s ...string) *IndexAdgangsAdresseQuery {
+	q.add("ejerlavkode", s...)
+	return q
+}
+
+// Matrikelnr will add a parameter for 'matrikelnr' to the IndexAdgangsAdresseQuery.
+func (q *IndexAdgangsAdresseQuery) Matrikelnr(s ...string) *IndexAdgangsAdresseQuery {
+	q.add("matrikelnr", s...)
+	return q
+}
+
+// Esrejendomsnr will add a parameter for 'esrejendomsnr' to the IndexAdgangsAdresseQuery.
+func (q *IndexAdgangsAdresseQuery) Esrejendomsnr(s ...string) *IndexAdgangsAdresseQuery {
+	q.add("esrejendomsnr", s...)
+	return q
+}
+
+// Regionskode will add a parameter for 'regionskode' to the IndexAdgangsAdresseQuery.
+func (q *IndexAdgangsAdresseQuery) Regionskode(s ...string) *IndexAdgangsAdresseQuery {
+	q.add("regionskode", s...)
+	return q
+}
+
+// Sognekode will add a parameter for 'sognekode' to the IndexAdgangsAdresseQuery.
+func (q *IndexAdgangsAdresseQuery) Sognekode(s ...string) *IndexAdgangsAdresseQuery {
+	q.add("sognekode", s...)
+	return q
+}
+
+// Opstillingskredskode will add a parameter for 'opstillingskredskode' to the IndexAdgangsAdresseQuery.
+func (q *IndexAdgangsAdresseQuery) Opstillingskredskode(s ...string) *IndexAdgangsAdresseQuery {
+	q.add("opstillingskredskode", s...)
+	return q
+}
+
+// Retskredskode will add a parameter for 'retskredskode' to the IndexAdgangsAdresseQuery.
+func (q *IndexAdgangsAdresseQuery) Retskredskode(s ...string) *IndexAdgangsAdresseQuery {
+	q.add("retskredskode", s...)
+	return q
+}
+
+// Politikredskode will add a parameter for 'politikredskode' to the IndexAdgangsAdresseQuery.
+func (q *IndexAdgangsAdresseQuery) Politikredskode(s ...string) *IndexAdgangsAdresseQuery {
+	q.add("politikredskode", s...)
+	return q
+}
+
+func indexFirst[T any](found []T) (*T, error) {
+	if len(found) == 0 {
+		return nil, io.EOF
+	}
+	return &found[0], nil
+}
+
+// indexIter returns an iterator over the found entries.
+func indexIter[T any](ctx context.Context, found []T) *Iter[T] {
+	ret := sliceIter(found...)
+	ret.ctx = ctx
+	return ret
+}
@@ -0,0 +1,118 @@
+package dawa
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+)
+
+func testIndex(t *testing.T) *Index {
+	idx := NewIndex()
+	iter, err := ImportAdresserCSV(bytes.NewBufferString(csv_data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.LoadAdresser(iter); err != nil {
+		t.Fatalf("LoadAdresser: %v", err)
+	}
+	aiter, err := ImportAdgangsAdresserCSV(bytes.NewBufferString(adgangs_csv_data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.LoadAdgangsAdresser(aiter); err != nil {
+		t.Fatalf("LoadAdgangsAdresser: %v", err)
+	}
+	return idx
+}
+
+func TestIndexAdresse(t *testing.T) {
+	idx := testIndex(t)
+	tests := []struct {
+		q    *IndexAdresseQuery
+		want int
+	}{
+		{idx.NewAdresseQuery(), 3},
+		{idx.NewAdresseQuery().ID("0a3f50b7-6544-32b8-e044-0003ba298018"), 1},
+		{idx.NewAdresseQuery().ID("0a3f50b7-6544-32b8-e044-0003ba298018", "0a3f50b7-6545-32b8-e044-0003ba298018"), 2},
+		{idx.NewAdresseQuery().ID("unknown"), 0},
+		{idx.NewAdresseQuery().Kvhx("05500001___8_______"), 1},
+		{idx.NewAdresseQuery().Postnr("6792").Vejnavn("A Hansensvej"), 3},
+		{idx.NewAdresseQuery().Postnr("6792").Vejnavn("A Hansensvej").Husnr("6"), 1},
+		{idx.NewAdresseQuery().Postnr("6792").Vejnavn("a hansensvej"), 0},
+		{idx.NewAdresseQuery().Kommunekode("0550").Vejkode("0001").Husnr("5", "8"), 2},
+		{idx.NewAdresseQuery().Ejerlavkode("1470852").Matrikelnr("720"), 1},
+		{idx.NewAdresseQuery().Husnr("5").Etage(), 1},
+		{idx.NewAdresseQuery().Etage("1"), 0},
+		{idx.NewAdresseQuery().Status(1).Regionskode("1083"), 3},
+	}
+	for i, test := range tests {
+		all, err := test.q.All()
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+		if len(all) != test.want {
+			t.Fatalf("test %d: expected %d results, got %d", i, test.want, len(all))
+		}
+	}
+
+	a, err := idx.GetAdresseID("0a3f50b7-6547-32b8-e044-0003ba298018")
+	if err != nil || a.Adgangsadresse.Husnr != "8" {
+		t.Fatalf("GetAdresseID: %+v, %v", a, err)
+	}
+	if _, err := idx.GetAdresseID("unknown"); err != io.EOF {
+		t.Fatalf("Expected io.EOF, got %v", err)
+	}
+
+	// Results should be returned in the order they were added.
+	iter, err := idx.NewAdresseQuery().Vejkode("0001").Kommunekode("0550").Iter()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var husnr []string
+	for a, err := range iter.All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		husnr = append(husnr, a.Adgangsadresse.Husnr)
+	}
+	if len(husnr) != 3 || husnr[0] != "6" || husnr[1] != "5" || husnr[2] != "8" {
+		t.Fatalf("Unexpected order: %v", husnr)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	iter, _ = idx.NewAdresseQuery().IterContext(ctx)
+	if _, err := iter.Next(); err != context.Canceled {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestIndexAdgangsAdresse(t *testing.T) {
+	idx := testIndex(t)
+	tests := []struct {
+		q    *IndexAdgangsAdresseQuery
+		want int
+	}{
+		{idx.NewAdgangsAdresseQuery(), 3},
+		{idx.NewAdgangsAdresseQuery().Kvh("01010004__3A"), 1},
+		{idx.NewAdgangsAdresseQuery().Postnr("1654").Vejnavn("Abel Cathrines Gade").Husnr("24", "28"), 2},
+		{idx.NewAdgangsAdresseQuery().Kommunekode("0101").Vejkode("0004"), 3},
+		{idx.NewAdgangsAdresseQuery().Ejerlavkode("2000174").Matrikelnr("70æ"), 2},
+		{idx.NewAdgangsAdresseQuery().SupplerendeBynavn(), 2},
+		{idx.NewAdgangsAdresseQuery().SupplerendeBynavn("Supp"), 1},
+	}
+	for i, test := range tests {
+		all, err := test.q.All()
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+		if len(all) != test.want {
+			t.Fatalf("test %d: expected %d results, got %d", i, test.want, len(all))
+		}
+	}
+	a, err := idx.GetAAID("0a3f507a-3680-32b8-e044-0003ba298018")
+	if err != nil || a.Husnr != "28" {
+		t.Fatalf("GetAAID: %+v, %v", a, err)
+	}
+}
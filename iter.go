@@ -0,0 +1,209 @@
+package dawa
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"iter"
+
+	"github.com/ugorji/go/codec"
+)
+
+// Iter is an Iterator that enable you to get individual entries.
+//
+// Use Next() to read entries one by one, or range over All().
+// If you stop reading before all entries have been returned,
+// call Close() to stop the producer and release the underlying stream.
+type Iter[T any] struct {
+	closer
+	a     chan T
+	want  chan struct{} // If not nil, the producer may wait for a receive on want before sending.
+	items []T           // Entries returned before reading from a. See sliceIter.
+	err   error         // Set by the producer before a is closed.
+	last  error         // Last error returned by Next.
+	ctx   context.Context
+}
+
+// newIter returns an iterator with the specified channel buffer size.
+// If ctx is cancelled, Next will return the context error.
+func newIter[T any](ctx context.Context, buffer int) *Iter[T] {
+	ret := &Iter[T]{a: make(chan T, buffer), ctx: ctx}
+	ret.init(func() {
+		for range ret.a {
+		}
+	})
+	return ret
+}
+
+// sliceIter returns an iterator that will return the supplied items.
+// The items are returned from the slice, without passing them through the channel.
+func sliceIter[T any](items ...T) *Iter[T] {
+	ret := &Iter[T]{a: make(chan T), items: items, ctx: context.Background()}
+	ret.init(func() {
+		ret.items = nil
+	})
+	close(ret.a)
+	ret.err = io.EOF
+	return ret
+}
+
+// Next will return the next entry.
+// It will return an error if that has been encountered.
+// When there are not more entries nil, io.EOF will be returned.
+func (a *Iter[T]) Next() (*T, error) {
+	if err := a.ctx.Err(); err != nil {
+		a.last = err
+		return nil, err
+	}
+	if len(a.items) > 0 {
+		v := a.items[0]
+		a.items = a.items[1:]
+		return &v, nil
+	}
+	var v T
+	var ok bool
+	select {
+	case v, ok = <-a.a:
+	case a.want <- struct{}{}:
+		v, ok = <-a.a
+	}
+	if ok {
+		return &v, nil
+	}
+	a.last = a.err
+	return nil, a.err
+}
+
+// Err returns the error that stopped the iteration.
+// If all entries were read, or the iteration hasn't stopped, nil is returned.
+func (a *Iter[T]) Err() error {
+	if a.last == io.EOF {
+		return nil
+	}
+	return a.last
+}
+
+// All returns an iterator that can be used with range.
+// If an error is encountered, it is returned as the last value.
+// The iterator is closed when the loop ends.
+//
+// Example:
+//			for a, err := range iter.All() {
+//				if err != nil {
+//					panic(err)
+//				}
+//				fmt.Printf("%+v\n", a)
+//			}
+func (a *Iter[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		defer a.Close()
+		for {
+			v, err := a.Next()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			if !yield(*v, nil) {
+				return
+			}
+		}
+	}
+}
+
+// nextAny returns the next entry untyped.
+func (a *Iter[T]) nextAny() (interface{}, error) {
+	v, err := a.Next()
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// send will send v to the consumer.
+// Returns false if the iterator has been closed or the context has been cancelled.
+func (a *Iter[T]) send(v T) bool {
+	select {
+	case a.a <- v:
+		return true
+	case <-a.done:
+	case <-a.ctx.Done():
+	}
+	return false
+}
+
+// importJSON will import a JSON array of T from the supplied reader.
+// If ctx is cancelled, the iterator will return the context error
+// and the decoding goroutine will be stopped.
+func importJSON[T any](ctx context.Context, in io.Reader) *Iter[T] {
+	var h codec.JsonHandle
+	h.DecodeOptions.ErrorIfNoField = JSONStrictFieldCheck
+	ret := newIter[T](ctx, 100)
+	// use a buffered reader for efficiency
+	end := &jsonEndReader{r: ret.reader(in)}
+	in = bufio.NewReader(end)
+	// When ctx is cancelled we stop the decoder and drain the channel,
+	// so the decoder is not blocked sending.
+	stop := context.AfterFunc(ctx, func() {
+		ret.stop()
+		ret.drain()
+	})
+	go func() {
+		defer stop()
+		defer close(ret.a)
+		var dec *codec.Decoder = codec.NewDecoder(in, &h)
+		// Decode into a copy, since a null input will set the channel to nil.
+		ch := ret.a
+		ret.err = ret.closedErr(dec.Decode(&ch))
+		if (ret.err == nil || ret.err == io.EOF) && end.truncated() {
+			ret.err = io.ErrUnexpectedEOF
+		}
+		if ret.err == nil {
+			ret.err = io.EOF
+		}
+	}()
+	return ret
+}
+
+// jsonEndReader keeps the last non-whitespace byte read from r.
+// Some versions of the decoder return no error or io.EOF if the input ends inside the array,
+// so this is used to check that the input wasn't truncated.
+type jsonEndReader struct {
+	r    io.Reader
+	last byte
+	eof  bool
+}
+
+func (j *jsonEndReader) Read(b []byte) (int, error) {
+	n, err := j.r.Read(b)
+	for i := n - 1; i >= 0; i-- {
+		if c := b[i]; c != ' ' && c != '\t' && c != '\r' && c != '\n' {
+			j.last = c
+			break
+		}
+	}
+	if err == io.EOF {
+		j.eof = true
+	}
+	return n, err
+}
+
+// truncated returns true if the input ended before the closing bracket of the array, or of null.
+func (j *jsonEndReader) truncated() bool {
+	return j.eof && j.last != 0 && j.last != ']' && j.last != 'l'
+}
+
+// importJSONFile will import a JSON array of T from a file, supplied to the reader.
+// gzip, zstd and zip compressed files are decompressed, see decompress.
+func importJSONFile[T any](in io.Reader) (*Iter[T], error) {
+	r, c, err := decompress(in, ".json")
+	if err != nil {
+		return nil, err
+	}
+	iter := importJSON[T](context.Background(), r)
+	iter.AddCloser(c)
+	return iter, nil
+}
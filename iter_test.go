@@ -0,0 +1,210 @@
+package dawa
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIterAll(t *testing.T) {
+	iter, err := ImportPostnumreJSON(strings.NewReader(postnumre_json_input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for p, err := range iter.All() {
+		if err != nil {
+			t.Fatalf("All: %v", err)
+		}
+		got = append(got, p.Nr)
+	}
+	if strings.Join(got, ",") != "9981,9982,9990" {
+		t.Fatalf("Unexpected result: %v", got)
+	}
+	if err := iter.Err(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Errors should be returned as last value.
+	// Truncated input is an error, whether it ends inside an entry or between them.
+	for _, in := range []string{`[{"nr":"9981"}, {"nr":`, `[{"nr":"9981"}, `, `[{"nr":"9981"}` + "\n"} {
+		iter, _ = ImportPostnumreJSON(strings.NewReader(in))
+		var last error
+		n := 0
+		for _, err := range iter.All() {
+			n++
+			last = err
+		}
+		if n != 2 || last == nil {
+			t.Fatalf("%s: expected 2 values ending with an error, got %d, %v", in, n, last)
+		}
+		if iter.Err() == nil {
+			t.Fatalf("%s: expected Err() to return error", in)
+		}
+	}
+
+	// Breaking early should close the iterator.
+	iter, _ = ImportPostnumreJSON(strings.NewReader(repeatJSON(`{"nr":"9981"}`, 1000)))
+	for range iter.All() {
+		break
+	}
+	if _, err := iter.Next(); err != ErrIteratorClosed {
+		t.Fatalf("Expected ErrIteratorClosed, got %v", err)
+	}
+}
+
+func TestListQueryOf(t *testing.T) {
+	tests := []qb{
+		{ListQueryOf[Region](false).URL(), DefaultHost + "/regioner"},
+		{ListQueryOf[Kommune](true).URL(), DefaultHost + "/kommuner/autocomplete"},
+		{ListQueryOf[Sogn](false).Q("a*").Kode("1", "2").Navn("b").URL(), DefaultHost + "/sogne?q=a%2A&kode=1|2&navn=b"},
+		{ListQueryOf[Postnummer](false).URL(), DefaultHost + "/postnumre"},
+	}
+	for _, q := range tests {
+		if q.Got != q.Expected {
+			t.Fatalf("Unexpected URL:\n     Was:\t%s\nExpected:\t%s", q.Got, q.Expected)
+		}
+	}
+	// All list types must resolve to a name the untyped query knows.
+	for _, name := range []string{
+		listTypeOf[Region](), listTypeOf[Kommune](), listTypeOf[Sogn](), listTypeOf[Retskreds](),
+		listTypeOf[Politikreds](), listTypeOf[Opstillingskreds](), listTypeOf[Valglandsdel](),
+		listTypeOf[Ejerlav](), listTypeOf[AdgangsAdresse](), listTypeOf[Adresse](), listTypeOf[Postnummer](),
+	} {
+		if NewListQuery(name, false).Type() == nil {
+			t.Fatalf("list type did not resolve:%s", name)
+		}
+		if _, ok := listIters[name]; !ok {
+			t.Fatalf("no iterator for list type:%s", name)
+		}
+	}
+}
+
+func TestListIterTyped(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/reverse") {
+			w.Write([]byte(`{"kode":"1084","navn":"Region Hovedstaden"}`))
+			return
+		}
+		w.Write([]byte(`[{"kode":"1084","navn":"Region Hovedstaden"},{"kode":"1085","navn":"Region Sjælland"}]`))
+	}))
+	defer ts.Close()
+	c := &Client{BaseURL: ts.URL}
+
+	all, err := ClientListQueryOf[Region](c, false).All()
+	if err != nil {
+		t.Fatalf("All: %v", err)
+	}
+	if len(all) != 2 || all[1].Navn != "Region Sjælland" {
+		t.Fatalf("Unexpected result: %+v", all)
+	}
+
+	iter, err := c.NewListQuery("regioner", false).Iter()
+	if err != nil {
+		t.Fatalf("Iter: %v", err)
+	}
+	defer iter.Close()
+	if _, err := iter.NextKommune(); err == nil {
+		t.Fatal("Expected error when requesting wrong type")
+	}
+	r, err := iter.NextRegion()
+	if err != nil || r.Kode != "1084" {
+		t.Fatalf("NextRegion: %+v, %v", r, err)
+	}
+	v, err := iter.Next()
+	if _, ok := v.(*Region); !ok || err != nil {
+		t.Fatalf("Next: %T, %v", v, err)
+	}
+	if v, err := iter.Next(); v != nil || err != io.EOF {
+		t.Fatalf("Expected nil, io.EOF, got %v, %v", v, err)
+	}
+
+	rev, err := ClientReverseQueryOf[Region](c, WGS84Point(12.58, 55.68))
+	if err != nil {
+		t.Fatalf("ClientReverseQueryOf: %v", err)
+	}
+	r, err = rev.Next()
+	if err != nil || r.Navn != "Region Hovedstaden" {
+		t.Fatalf("Reverse Next: %+v, %v", r, err)
+	}
+	if _, err := rev.Next(); !errors.Is(err, io.EOF) {
+		t.Fatalf("Expected io.EOF, got %v", err)
+	}
+
+	li, err := c.NewReverseQuery("regioner", WGS84Point(12.58, 55.68))
+	if err != nil {
+		t.Fatalf("NewReverseQuery: %v", err)
+	}
+	if r, err := li.NextRegion(); err != nil || r.Kode != "1084" {
+		t.Fatalf("NextRegion: %+v, %v", r, err)
+	}
+}
+
+func TestIterNull(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("null"))
+	}))
+	defer ts.Close()
+	c := &Client{BaseURL: ts.URL}
+
+	// A null list must not leave the iterator without a channel,
+	// which made Next and Close block forever.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		res, err := c.NewAdresseQuery().Postnr("1234").All()
+		if err != nil || len(res) != 0 {
+			t.Errorf("Unexpected result: %v, %v", res, err)
+		}
+		iter := importJSON[Postnummer](context.Background(), strings.NewReader("null"))
+		if _, err := iter.Next(); err != io.EOF {
+			t.Errorf("Expected io.EOF, got %v", err)
+		}
+		iter.Close()
+		iter = importJSON[Postnummer](context.Background(), strings.NewReader("null"))
+		iter.Close()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Iterator of null list blocked")
+	}
+}
+
+func TestSliceIter(t *testing.T) {
+	items := []Postnummer{{Nr: "9981"}, {Nr: "9982"}, {Nr: "9990"}}
+	iter := sliceIter(items...)
+	if len(iter.a) != 0 || cap(iter.a) != 0 {
+		t.Fatalf("Items were copied to the channel")
+	}
+	for i := range items {
+		p, err := iter.Next()
+		if err != nil || p.Nr != items[i].Nr {
+			t.Fatalf("Next: %+v, %v", p, err)
+		}
+	}
+	if _, err := iter.Next(); err != io.EOF {
+		t.Fatalf("Expected io.EOF, got %v", err)
+	}
+
+	// Closing stops the iteration.
+	iter = sliceIter(items...)
+	iter.Next()
+	iter.Close()
+	if _, err := iter.Next(); err != io.EOF {
+		t.Fatalf("Expected io.EOF, got %v", err)
+	}
+
+	// The context of an index query is checked.
+	ctx, cancel := context.WithCancel(context.Background())
+	iter = indexIter(ctx, items)
+	cancel()
+	if _, err := iter.Next(); err != context.Canceled {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,248 @@
+package dawa
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// KVH is a KVH-nøgle for an adgangsadresse.
+// 12 tegn bestående af 4 cifre der repræsenterer kommunekode,
+// 4 cifre der repræsenterer vejkode efterfulgt af 4 tegn der repræsenter husnr.
+// Husnr is padded with '_' on the left, for example "01010004__3A".
+//
+// Use ParseKVH to validate a key, or FormatKVH to create one.
+// The accessors return "" if the key is too short.
+type KVH string
+
+// KVHX is a KVHX-nøgle for an adresse.
+// 19 tegn bestående af 4 cifre der repræsenterer kommunekode,
+// 4 cifre der repræsenterer vejkode, 4 tegn der repræsenter husnr,
+// 3 tegn der repræsenterer etage og 4 tegn der repræsenter dør.
+// Husnr, etage and dør are padded with '_' on the left, for example "05500001___6__1__tv".
+//
+// Use ParseKVHX to validate a key, or FormatKVHX to create one.
+// The accessors return "" if the key is too short.
+type KVHX string
+
+// Widths of the fields in KVH and KVHX keys.
+const (
+	kvhKommuneWidth = 4
+	kvhVejWidth     = 4
+	kvhHusnrWidth   = 4
+	kvhxEtageWidth  = 3
+	kvhxDørWidth    = 4
+	kvhWidth        = kvhKommuneWidth + kvhVejWidth + kvhHusnrWidth
+	kvhxWidth       = kvhWidth + kvhxEtageWidth + kvhxDørWidth
+)
+
+var (
+	reKvhKode  = regexp.MustCompile(`^[0-9]{4}$`)
+	reKvhHusnr = regexp.MustCompile(`^[1-9][0-9]{0,2}[A-Z]?$|^[1-9][0-9]{3}$`)
+	reKvhEtage = regexp.MustCompile(`^(st|kl[1-9]?|[1-9][0-9]?)$`)
+	reKvhDør   = regexp.MustCompile(`^[0-9a-zA-ZæøåÆØÅ/\-]{1,4}$`)
+)
+
+// ParseKVH will parse and validate a KVH key.
+func ParseKVH(s string) (KVH, error) {
+	k := KVH(s)
+	return k, k.Validate()
+}
+
+// ParseKVHX will parse and validate a KVHX key.
+func ParseKVHX(s string) (KVHX, error) {
+	k := KVHX(s)
+	return k, k.Validate()
+}
+
+// FormatKVH returns the KVH key for the values.
+// Kommunekode and vejkode are padded with zeros, and husnr with '_' to the width of the key.
+// Letters in husnr are converted to upper case.
+// An error is returned if a value is invalid.
+func FormatKVH(kommunekode, vejkode, husnr string) (KVH, error) {
+	husnr = strings.ToUpper(husnr)
+	k := KVH(padLeft(kommunekode, kvhKommuneWidth, "0") +
+		padLeft(vejkode, kvhVejWidth, "0") +
+		padLeft(husnr, kvhHusnrWidth, "_"))
+	if reason := k.problem(kommunekode, vejkode, husnr); reason != "" {
+		return k, keyError("kvh", string(k), reason)
+	}
+	return k, nil
+}
+
+// FormatKVHX returns the KVHX key for the values.
+// Kommunekode and vejkode are padded with zeros, and husnr, etage and dør with '_' to the width of the key.
+// Letters in husnr are converted to upper case.
+// An error is returned if a value is invalid.
+func FormatKVHX(kommunekode, vejkode, husnr, etage, dør string) (KVHX, error) {
+	kvh, _ := FormatKVH(kommunekode, vejkode, husnr)
+	k := KVHX(string(kvh) + padLeft(etage, kvhxEtageWidth, "_") + padLeft(dør, kvhxDørWidth, "_"))
+	reason := kvh.problem(kommunekode, vejkode, strings.ToUpper(husnr))
+	if reason == "" {
+		reason = k.problem(etage, dør)
+	}
+	if reason != "" {
+		return k, keyError("kvhx", string(k), reason)
+	}
+	return k, nil
+}
+
+// Validate returns an error if the key is invalid.
+func (k KVH) Validate() error {
+	reason := lengthProblem(string(k), kvhWidth)
+	if reason == "" {
+		reason = k.problem(k.Kommunekode(), k.Vejkode(), k.Husnr())
+	}
+	if reason != "" {
+		return keyError("kvh", string(k), reason)
+	}
+	return nil
+}
+
+// problem returns the reason the values are invalid, or "" if they are valid.
+// The values are also checked against the key, so values that do not fit are found.
+func (k KVH) problem(kommunekode, vejkode, husnr string) string {
+	switch {
+	case len(kommunekode) > kvhKommuneWidth || !reKvhKode.MatchString(k.Kommunekode()):
+		return fmt.Sprintf("kommunekode %q must be up to 4 digits", kommunekode)
+	case len(vejkode) > kvhVejWidth || !reKvhKode.MatchString(k.Vejkode()):
+		return fmt.Sprintf("vejkode %q must be up to 4 digits", vejkode)
+	case !reKvhHusnr.MatchString(husnr) || husnr != k.Husnr():
+		return fmt.Sprintf("husnr %q must be up to 3 digits and a letter, or 4 digits, without leading zeros", husnr)
+	}
+	return ""
+}
+
+// Validate returns an error if the key is invalid.
+func (k KVHX) Validate() error {
+	reason := lengthProblem(string(k), kvhxWidth)
+	if reason == "" {
+		reason = k.KVH().problem(k.Kommunekode(), k.Vejkode(), k.Husnr())
+	}
+	if reason == "" {
+		reason = k.problem(k.Etage(), k.Dør())
+	}
+	if reason != "" {
+		return keyError("kvhx", string(k), reason)
+	}
+	return nil
+}
+
+// problem returns the reason etage and dør are invalid, or "" if they are valid.
+func (k KVHX) problem(etage, dør string) string {
+	switch {
+	case etage != "" && (!reKvhEtage.MatchString(etage) || etage != k.Etage()):
+		return fmt.Sprintf("etage %q must be st, kl, kl1-kl9 or 1-99", etage)
+	case dør != "" && (!reKvhDør.MatchString(dør) || dør != k.Dør()):
+		return fmt.Sprintf("dør %q must be up to 4 letters, digits, '/' or '-'", dør)
+	}
+	return ""
+}
+
+// lengthProblem returns the reason if s doesn't have the width.
+func lengthProblem(s string, width int) string {
+	if n := len([]rune(s)); n != width {
+		return fmt.Sprintf("length is %d, must be %d", n, width)
+	}
+	return ""
+}
+
+// keyError returns an error for an invalid key.
+func keyError(kind, key, reason string) error {
+	return fmt.Errorf("dawa: invalid %s %q: %s", kind, key, reason)
+}
+
+// String returns the key as a string.
+func (k KVH) String() string {
+	return string(k)
+}
+
+// Kommunekode returns the kommunekode of the key.
+func (k KVH) Kommunekode() string {
+	return keyField(string(k), 0, kvhKommuneWidth)
+}
+
+// Vejkode returns the vejkode of the key.
+func (k KVH) Vejkode() string {
+	return keyField(string(k), kvhKommuneWidth, kvhVejWidth)
+}
+
+// Husnr returns the husnr of the key, without padding.
+func (k KVH) Husnr() string {
+	return keyField(string(k), kvhKommuneWidth+kvhVejWidth, kvhHusnrWidth)
+}
+
+// String returns the key as a string.
+func (k KVHX) String() string {
+	return string(k)
+}
+
+// KVH returns the KVH part of the key.
+// If the key is too short, "" is returned.
+func (k KVHX) KVH() KVH {
+	r := []rune(k)
+	if len(r) < kvhWidth {
+		return ""
+	}
+	return KVH(r[:kvhWidth])
+}
+
+// Kommunekode returns the kommunekode of the key.
+func (k KVHX) Kommunekode() string {
+	return keyField(string(k), 0, kvhKommuneWidth)
+}
+
+// Vejkode returns the vejkode of the key.
+func (k KVHX) Vejkode() string {
+	return keyField(string(k), kvhKommuneWidth, kvhVejWidth)
+}
+
+// Husnr returns the husnr of the key, without padding.
+func (k KVHX) Husnr() string {
+	return keyField(string(k), kvhKommuneWidth+kvhVejWidth, kvhHusnrWidth)
+}
+
+// Etage returns the etage of the key, without padding.
+func (k KVHX) Etage() string {
+	return keyField(string(k), kvhWidth, kvhxEtageWidth)
+}
+
+// Dør returns the dør of the key, without padding.
+func (k KVHX) Dør() string {
+	return keyField(string(k), kvhWidth+kvhxEtageWidth, kvhxDørWidth)
+}
+
+// KVH returns the KVH key computed from the kommunekode, vejkode and husnr of the adgangsadresse.
+//
+// The method is named KVH, since the name Kvh is used by the field with the key returned by DAWA.
+func (a AdgangsAdresse) KVH() (KVH, error) {
+	return FormatKVH(a.Kommune.Kode, a.Vejstykke.Kode, a.Husnr)
+}
+
+// KVHX returns the KVHX key computed from the kommunekode, vejkode, husnr, etage and dør of the adresse.
+// Use this to check or fill the Kvhx field.
+//
+// The method is named KVHX and not Kvhx, since a method cannot have
+// the same name as the Kvhx field with the key returned by DAWA.
+func (a Adresse) KVHX() (KVHX, error) {
+	aa := &a.Adgangsadresse
+	return FormatKVHX(aa.Kommune.Kode, aa.Vejstykke.Kode, aa.Husnr, a.Etage, a.Dør)
+}
+
+// keyField returns the field at the rune offset with the width, without padding.
+// If s is too short, "" is returned.
+func keyField(s string, offset, width int) string {
+	r := []rune(s)
+	if len(r) < offset+width {
+		return ""
+	}
+	return strings.TrimLeft(string(r[offset:offset+width]), "_")
+}
+
+// padLeft will pad s to the width using pad.
+func padLeft(s string, width int, pad string) string {
+	if n := len([]rune(s)); n < width {
+		return strings.Repeat(pad, width-n) + s
+	}
+	return s
+}
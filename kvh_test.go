@@ -0,0 +1,157 @@
+package dawa
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseKVHX(t *testing.T) {
+	k, err := ParseKVHX("05630110___1__1__tv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k.Kommunekode() != "0563" || k.Vejkode() != "0110" || k.Husnr() != "1" || k.Etage() != "1" || k.Dør() != "tv" {
+		t.Fatalf("Unexpected fields: %q %q %q %q %q", k.Kommunekode(), k.Vejkode(), k.Husnr(), k.Etage(), k.Dør())
+	}
+	if k.KVH() != "05630110___1" || k.String() != "05630110___1__1__tv" {
+		t.Fatalf("Unexpected kvh: %q", k.KVH())
+	}
+
+	valid := []string{"05500001___6_______", "01010004_12A_st____", "01010004__10kl2_1/2", "010100049999_99___1"}
+	for _, s := range valid {
+		if _, err := ParseKVHX(s); err != nil {
+			t.Errorf("ParseKVHX(%q): %v", s, err)
+		}
+	}
+	invalid := map[string]string{
+		"":                     "length is 0",
+		"05500001___6":         "length is 12",
+		"0550000____6_______":  "vejkode",
+		"_5500001___6_______":  "kommunekode",
+		"05500001____________": "length is 20",
+		"05500001____st____":   "length is 18",
+		"05500001___________":  "husnr",
+		"055000010006_______":  "husnr",
+		"05500001__6a_______":  "husnr",
+		"05500001___6_x_____":  "etage",
+		"05500001___6100____":  "etage",
+		"05500001___6____t_v":  "dør",
+		"05500001___6___tv.x":  "dør",
+	}
+	for s, reason := range invalid {
+		_, err := ParseKVHX(s)
+		if err == nil {
+			t.Errorf("ParseKVHX(%q): expected error", s)
+			continue
+		}
+		if !strings.Contains(err.Error(), reason) || !strings.HasPrefix(err.Error(), "dawa: invalid kvhx") {
+			t.Errorf("ParseKVHX(%q): expected error containing %q, got %v", s, reason, err)
+		}
+	}
+
+	// Accessors must not panic on short keys.
+	k = KVHX("0550")
+	if k.Kommunekode() != "0550" || k.Vejkode() != "" || k.KVH() != "" || k.Dør() != "" {
+		t.Fatalf("Unexpected fields of short key")
+	}
+}
+
+func TestParseKVH(t *testing.T) {
+	k, err := ParseKVH("01010004__3A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k.Kommunekode() != "0101" || k.Vejkode() != "0004" || k.Husnr() != "3A" {
+		t.Fatalf("Unexpected fields: %q %q %q", k.Kommunekode(), k.Vejkode(), k.Husnr())
+	}
+	for _, s := range []string{"", "01010004__3", "01010004__3a", "0101000x__3A", "01010004__3A_"} {
+		if _, err := ParseKVH(s); err == nil || !strings.HasPrefix(err.Error(), "dawa: invalid kvh") {
+			t.Errorf("ParseKVH(%q): expected error, got %v", s, err)
+		}
+	}
+}
+
+func TestFormatKVHX(t *testing.T) {
+	tests := []struct {
+		kommune, vej, husnr, etage, dør string
+		expect                          KVHX
+	}{
+		{"0550", "0001", "6", "", "", "05500001___6_______"},
+		{"550", "1", "6", "", "", "05500001___6_______"},
+		{"0101", "0004", "3a", "st", "tv", "01010004__3A_st__tv"},
+		{"0101", "0004", "123B", "kl", "1", "01010004123B_kl___1"},
+		{"0563", "0110", "1", "1", "", "05630110___1__1____"},
+	}
+	for _, test := range tests {
+		got, err := FormatKVHX(test.kommune, test.vej, test.husnr, test.etage, test.dør)
+		if err != nil {
+			t.Errorf("FormatKVHX(%+v): %v", test, err)
+			continue
+		}
+		if got != test.expect {
+			t.Errorf("FormatKVHX(%+v): got %q, expected %q", test, got, test.expect)
+		}
+		if err := got.Validate(); err != nil {
+			t.Errorf("Validate(%q): %v", got, err)
+		}
+	}
+
+	invalid := map[[5]string]string{
+		{"05501", "0001", "6", "", ""}:    "kommunekode",
+		{"0550", "x", "6", "", ""}:        "vejkode",
+		{"0550", "0001", "", "", ""}:      "husnr",
+		{"0550", "0001", "12345", "", ""}: "husnr",
+		{"0550", "0001", "6", "sal", ""}:  "etage",
+		{"0550", "0001", "6", "1", "tv."}: "dør",
+		{"0550", "0001", "6", "1", "_tv"}: "dør",
+	}
+	for in, reason := range invalid {
+		_, err := FormatKVHX(in[0], in[1], in[2], in[3], in[4])
+		if err == nil || !strings.Contains(err.Error(), reason) {
+			t.Errorf("FormatKVHX(%q): expected error containing %q, got %v", in, reason, err)
+		}
+	}
+	if _, err := FormatKVH("0550", "0001", "6 B"); err == nil || !strings.Contains(err.Error(), "husnr") {
+		t.Errorf("FormatKVH: expected husnr error, got %v", err)
+	}
+}
+
+func TestAdresseKVHX(t *testing.T) {
+	iter, err := ImportAdresserCSV(strings.NewReader(csv_data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		a, err := iter.Next()
+		if err != nil {
+			break
+		}
+		k, err := a.KVHX()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(k) != a.Kvhx {
+			t.Errorf("Got %q, expected %q", k, a.Kvhx)
+		}
+		kvh, err := a.Adgangsadresse.KVH()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(kvh) != a.Adgangsadresse.Kvh {
+			t.Errorf("Got %q, expected %q", kvh, a.Adgangsadresse.Kvh)
+		}
+	}
+
+	// A short kvhx must not panic.
+	iter, err = ImportAdresserCSV(strings.NewReader(strings.Replace(csv_data, "05500001___6_______", "0550", 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := iter.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Kvhx != "0550" || a.Adgangsadresse.Kvh != "" {
+		t.Fatalf("Unexpected keys: %q %q", a.Kvhx, a.Adgangsadresse.Kvh)
+	}
+}
@@ -1,13 +1,11 @@
 package dawa
 
 import (
-	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
-	"github.com/ugorji/go/codec"
 	"io"
 	"io/ioutil"
-	"reflect"
 	"strconv"
 )
 
@@ -26,17 +24,13 @@ type ListQuery struct {
 //
 // Supported list types are "regioner","sogne","retskredse","politikredse","opstillingskredse","valglandsdele","ejerlav", "adgangsadresser", "adresser" or "postnumre".
 // Use the corresponding iterator function, for instance i.NextRegion() to get typed results.
+// To get a typed query, use ListQueryOf().
 //
 // See 'examples/query-list.go' for a usage example.
 //
 // See documentation at http://dawa.aws.dk/listerdok
 func NewListQuery(listType string, autoComplete bool) *ListQuery {
-	path := "/" + listType
-	if autoComplete {
-		path += "/autocomplete"
-	}
-	q := &ListQuery{listType: listType, queryGeoJSON: queryGeoJSON{query: query{host: DefaultHost, path: path}}}
-	return q
+	return DefaultClient.NewListQuery(listType, autoComplete)
 }
 
 // Q will add a parameter for 'q' to the ListQuery.
@@ -73,53 +67,67 @@ func (q *ListQuery) NoFormat() *ListQuery {
 
 // ListIter is an Iterator that enable you to get individual entries.
 type ListIter struct {
-	closer
-	a     reflect.Value // Channel
-	eType reflect.Type  // Type of the element
-	err   error
+	it  anyIter
+	typ interface{} // The element type, see ListQuery.Type()
 }
 
-func makeChannel(t reflect.Type, chanDir reflect.ChanDir, buffer int) reflect.Value {
-	ctype := reflect.ChanOf(chanDir, t)
-	return reflect.MakeChan(ctype, buffer)
+// anyIter is implemented by Iter[T] for all types.
+type anyIter interface {
+	nextAny() (interface{}, error)
+	AddCloser(io.Closer)
+	Close() error
 }
 
 // Iter creates a list iterator that will allow you to get the items one by one.
 //
 func (q ListQuery) Iter() (*ListIter, error) {
-	resp, err := q.NoFormat().Request()
+	return q.IterContext(context.Background())
+}
+
+// IterContext creates a list iterator like Iter().
+// If ctx is cancelled, the request is aborted and
+// the iterator will return the context error.
+func (q ListQuery) IterContext(ctx context.Context) (*ListIter, error) {
+	iter, ok := listIters[q.listType]
+	if !ok {
+		return nil, fmt.Errorf("Unknown list type: %s", q.listType)
+	}
+	it, err := iter(ctx, q.query, false)
 	if err != nil {
 		return nil, err
 	}
+	return &ListIter{it: it, typ: q.Type()}, nil
+}
 
-	typ := q.Type()
-	if typ == nil {
-		return nil, fmt.Errorf("Unknown list type: %s", q.listType)
-	}
-	var h codec.JsonHandle
-	h.DecodeOptions.ErrorIfNoField = JSONStrictFieldCheck
-
-	// use a buffered reader for efficiency
-	in := bufio.NewReader(resp)
-	ret := &ListIter{}
-	ret.eType = reflect.TypeOf(typ)
-	// We create a channel with the expected type
-	ret.a = makeChannel(ret.eType, reflect.BothDir, 100)
-	go func() {
-		defer ret.a.Close()
-		var dec *codec.Decoder = codec.NewDecoder(in, &h)
-		channel := ret.a.Interface()
-		ret.err = dec.Decode(&channel)
-		if ret.err == nil {
-			ret.err = io.EOF
-		}
-	}()
+// listIters contains iterator functions for all known list types.
+var listIters = map[string]func(ctx context.Context, q query, reverse bool) (anyIter, error){
+	"kommuner":          listIter[Kommune],
+	"regioner":          listIter[Region],
+	"sogne":             listIter[Sogn],
+	"retskredse":        listIter[Retskreds],
+	"politikredse":      listIter[Politikreds],
+	"opstillingskredse": listIter[Opstillingskreds],
+	"valglandsdele":     listIter[Valglandsdel],
+	"ejerlav":           listIter[Ejerlav],
+	"adgangsadresser":   listIter[AdgangsAdresse],
+	"adresser":          listIter[Adresse],
+	"postnumre":         listIter[Postnummer],
+}
 
+// listIter will execute the query and return an iterator of T.
+// If reverse is true, a single object is expected as result.
+func listIter[T any](ctx context.Context, q query, reverse bool) (anyIter, error) {
+	var it *Iter[T]
+	var err error
+	if reverse {
+		it, err = reverseQuery[T](ctx, q)
+	} else {
+		it, err = iterQuery[T](ctx, q)
+	}
 	if err != nil {
 		return nil, err
 	}
-	ret.AddCloser(resp)
-	return ret, nil
+	return it, nil
 }
 
 // Returns a writeable
@@ -155,201 +163,335 @@ func (q ListQuery) Type() interface{} {
 // It will return an error if that has been encountered.
 // When there are not more entries nil, io.EOF will be returned.
 func (a *ListIter) Next() (interface{}, error) {
-	v, ok := a.a.Recv()
-	if ok {
-		return v.Interface(), nil
-	}
-	return nil, a.err
+	return a.it.nextAny()
+}
+
+// Call this when you are finished using the object
+func (a *ListIter) Close() error {
+	return a.it.Close()
+}
+
+func (a *ListIter) AddCloser(c io.Closer) {
+	a.it.AddCloser(c)
+}
+
+// nextAs will return the next item as T.
+// Returns an error if the iterator does not contain items of type T.
+func nextAs[T any](a *ListIter) (*T, error) {
+	it, ok := a.it.(*Iter[T])
+	if !ok {
+		return nil, fmt.Errorf("Wrong type requested from iterator. Expected %T", a.typ)
+	}
+	return it.Next()
 }
 
 // NextKommune will return the next item.
 // The query must be built using the corresponding type. See NewListQuery() function.
 func (a *ListIter) NextKommune() (*Kommune, error) {
-	if !a.eType.ConvertibleTo(reflect.TypeOf(&Kommune{})) {
-		return nil, fmt.Errorf("Wrong type requested from iterator. Expected %s", a.eType.String())
-	}
-	item, err := a.Next()
-	if err != nil {
-		return nil, a.err
-	}
-	return item.(*Kommune), nil
+	return nextAs[Kommune](a)
 }
 
 // NextRegion will return the next item.
 // The query must be built using the corresponding type. See NewListQuery() function.
 func (a *ListIter) NextRegion() (*Region, error) {
-	if !a.eType.ConvertibleTo(reflect.TypeOf(&Region{})) {
-		return nil, fmt.Errorf("Wrong type requested from iterator. Expected %s", a.eType.String())
-	}
-	item, err := a.Next()
-	if err != nil {
-		return nil, a.err
-	}
-	return item.(*Region), nil
+	return nextAs[Region](a)
 }
 
 // NextSogn will return the next item.
 // The query must be built using the corresponding type. See NewListQuery() function.
 func (a *ListIter) NextSogn() (*Sogn, error) {
-	if !a.eType.ConvertibleTo(reflect.TypeOf(&Sogn{})) {
-		return nil, fmt.Errorf("Wrong type requested from iterator. Expected %s", a.eType.String())
-	}
-	item, err := a.Next()
-	if err != nil {
-		return nil, a.err
-	}
-	return item.(*Sogn), nil
+	return nextAs[Sogn](a)
 }
 
 // NextRetskreds will return the next item.
 // The query must be built using the corresponding type. See NewListQuery() function.
 func (a *ListIter) NextRetskreds() (*Retskreds, error) {
-	if !a.eType.ConvertibleTo(reflect.TypeOf(&Retskreds{})) {
-		return nil, fmt.Errorf("Wrong type requested from iterator. Expected %s", a.eType.String())
-	}
-	item, err := a.Next()
-	if err != nil {
-		return nil, a.err
-	}
-	return item.(*Retskreds), nil
+	return nextAs[Retskreds](a)
 }
 
 // NextPolitikreds will return the next item.
 // The query must be built using the corresponding type. See NewListQuery() function.
 func (a *ListIter) NextPolitikreds() (*Politikreds, error) {
-	if !a.eType.ConvertibleTo(reflect.TypeOf(&Politikreds{})) {
-		return nil, fmt.Errorf("Wrong type requested from iterator. Expected %s", a.eType.String())
-	}
-	item, err := a.Next()
-	if err != nil {
-		return nil, a.err
-	}
-	return item.(*Politikreds), nil
+	return nextAs[Politikreds](a)
 }
 
 // NextOpstillingskreds will return the next item.
 // The query must be built using the corresponding type. See NewListQuery() function.
 func (a *ListIter) NextOpstillingskreds() (*Opstillingskreds, error) {
-	if !a.eType.ConvertibleTo(reflect.TypeOf(&Opstillingskreds{})) {
-		return nil, fmt.Errorf("Wrong type requested from iterator. Expected %s", a.eType.String())
-	}
-	item, err := a.Next()
-	if err != nil {
-		return nil, a.err
-	}
-	return item.(*Opstillingskreds), nil
+	return nextAs[Opstillingskreds](a)
 }
 
 // NextValglandsdel will return the next item.
 // The query must be built using the corresponding type. See NewListQuery() function.
 func (a *ListIter) NextValglandsdel() (*Valglandsdel, error) {
-	if !a.eType.ConvertibleTo(reflect.TypeOf(&Valglandsdel{})) {
-		return nil, fmt.Errorf("Wrong type requested from iterator. Expected %s", a.eType.String())
-	}
-	item, err := a.Next()
-	if err != nil {
-		return nil, a.err
-	}
-	return item.(*Valglandsdel), nil
+	return nextAs[Valglandsdel](a)
 }
 
 // NextEjerlav will return the next item.
 // The query must be built using the corresponding type. See NewListQuery() function.
 func (a *ListIter) NextEjerlav() (*Ejerlav, error) {
-	if !a.eType.ConvertibleTo(reflect.TypeOf(&Ejerlav{})) {
-		return nil, fmt.Errorf("Wrong type requested from iterator. Expected %s", a.eType.String())
-	}
-	item, err := a.Next()
-	if err != nil {
-		return nil, a.err
-	}
-	return item.(*Ejerlav), nil
+	return nextAs[Ejerlav](a)
 }
 
 // NextAdgangsAdresse will return the next item.
 // The query must be built using the corresponding type. See NewListQuery() function.
 func (a *ListIter) NextAdgangsAdresse() (*AdgangsAdresse, error) {
-	if !a.eType.ConvertibleTo(reflect.TypeOf(&AdgangsAdresse{})) {
-		return nil, fmt.Errorf("Wrong type requested from iterator. Expected %s", a.eType.String())
-	}
-	item, err := a.Next()
-	if err != nil {
-		return nil, a.err
-	}
-	return item.(*AdgangsAdresse), nil
+	return nextAs[AdgangsAdresse](a)
 }
 
 // NextAdresse will return the next item.
 // The query must be built using the corresponding type. See NewListQuery() function.
 func (a *ListIter) NextAdresse() (*Adresse, error) {
-	if !a.eType.ConvertibleTo(reflect.TypeOf(&Adresse{})) {
-		return nil, fmt.Errorf("Wrong type requested from iterator. Expected %s", a.eType.String())
-	}
-	item, err := a.Next()
-	if err != nil {
-		return nil, a.err
-	}
-	return item.(*Adresse), nil
+	return nextAs[Adresse](a)
 }
 
 // NextPostnummer will return the next item.
 // The query must be built using the corresponding type. See NewListQuery() function.
 func (a *ListIter) NextPostnummer() (*Postnummer, error) {
-	if !a.eType.ConvertibleTo(reflect.TypeOf(&Postnummer{})) {
-		return nil, fmt.Errorf("Wrong type requested from iterator. Expected %s", a.eType.String())
-	}
-	item, err := a.Next()
-	if err != nil {
-		return nil, a.err
-	}
-	return item.(*Postnummer), nil
+	return nextAs[Postnummer](a)
 }
 
 // NewReverseQuery will create a reverse location to item lookup. Parameters are:
 //
 //	* listType: See NewListQuery() for valid options.
-// 	* x: X koordinat. (Hvis ETRS89/UTM32 anvendes angives øst-værdien.) Hvis WGS84/geografisk anvendex angives bredde-værdien.
-// 	* y: Y koordinat. (Hvis ETRS89/UTM32 anvendes angives nord-værdien.) Hvis WGS84/geografisk anvendex angives længde-værdien.
-//	* srid: Angiver SRID for det koordinatsystem, som geospatiale parametre er angivet i. Default er 4326 (WGS84). Leave this empty for default value
+// 	* p: Koordinatet. Use WGS84Point(længde, bredde) or ETRS89Point(øst, nord).
+//	  The SRID of the point is sent with the query, unless it is the default, 4326 (WGS84).
 //
 //  See examples/query-list-reverse.go for usage example
 //
 // An iterator will be returned, but it will only contain zero or one values.
-func NewReverseQuery(listType string, x, y float64, srid string) (*ListIter, error) {
-	path := "/" + listType + "/reverse"
-	q := &ListQuery{listType: listType, queryGeoJSON: queryGeoJSON{query: query{host: DefaultHost, path: path}}}
-	typ := q.Type()
-	if typ == nil {
+func NewReverseQuery(listType string, p Point) (*ListIter, error) {
+	return DefaultClient.NewReverseQueryContext(context.Background(), listType, p)
+}
+
+// NewReverseQueryContext will do a reverse lookup like NewReverseQuery().
+// If ctx is cancelled, the request is aborted and the context error is returned.
+func NewReverseQueryContext(ctx context.Context, listType string, p Point) (*ListIter, error) {
+	return DefaultClient.NewReverseQueryContext(ctx, listType, p)
+}
+
+// NewReverseQuery will create a reverse location to item lookup using this client.
+// See the package level NewReverseQuery() for a description of the parameters.
+//
+// An iterator will be returned, but it will only contain zero or one values.
+func (c *Client) NewReverseQuery(listType string, p Point) (*ListIter, error) {
+	return c.NewReverseQueryContext(context.Background(), listType, p)
+}
+
+// NewReverseQueryContext will do a reverse lookup like NewReverseQuery() using this client.
+// If ctx is cancelled, the request is aborted and the context error is returned.
+func (c *Client) NewReverseQueryContext(ctx context.Context, listType string, p Point) (*ListIter, error) {
+	iter, ok := listIters[listType]
+	if !ok {
 		return nil, fmt.Errorf("unknown list type '%s'", listType)
 	}
-	q.add(&textQuery{Name: "x", Values: []string{strconv.FormatFloat(x, 'f', -1, 64)}, Multi: false, Null: false})
-	q.add(&textQuery{Name: "y", Values: []string{strconv.FormatFloat(y, 'f', -1, 64)}, Multi: false, Null: false})
-	if srid != "" {
-		q.add(&textQuery{Name: "srid", Values: []string{srid}, Multi: false, Null: false})
+	q, err := c.newReverseQuery(listType, p)
+	if err != nil {
+		return nil, err
 	}
-	// Execute request
-	resp, err := q.NoFormat().Request()
+	it, err := iter(ctx, q, true)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Close()
+	return &ListIter{it: it, typ: ListQuery{listType: listType}.Type()}, nil
+}
+
+// newReverseQuery returns a reverse lookup query for the list type.
+// An error is returned if the SRID of the point isn't supported.
+func (c *Client) newReverseQuery(listType string, p Point) (query, error) {
+	if p.SRID != 0 && !p.SRID.Valid() {
+		return query{}, fmt.Errorf("dawa: unsupported srid %d", p.SRID)
+	}
+	q := c.newQuery("/" + listType + "/reverse").query
+	q.add(&textQuery{Name: "x", Values: []string{strconv.FormatFloat(p.X, 'f', -1, 64)}, Multi: false, Null: false})
+	q.add(&textQuery{Name: "y", Values: []string{strconv.FormatFloat(p.Y, 'f', -1, 64)}, Multi: false, Null: false})
+	if p.srid() != WGS84 {
+		q.addSrid(p.SRID)
+	}
+	return q, nil
+}
 
-	// We create the iterator and fill it with data.
-	ret := &ListIter{}
-	ret.eType = reflect.TypeOf(typ)
-	ret.a = makeChannel(ret.eType, reflect.BothDir, 1)
+// reverseQuery will execute a reverse lookup query,
+// and return an iterator with the result.
+func reverseQuery[T any](ctx context.Context, q query) (*Iter[T], error) {
+	q = q.clone()
+	q.set(&textQuery{Name: "noformat", Null: true})
+	resp, err := q.RequestContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Close()
 
 	all, err := ioutil.ReadAll(resp)
 	if err != nil {
 		return nil, err
 	}
-	// Decode to typ
-	err = json.Unmarshal(all, typ)
+	var v T
+	err = json.Unmarshal(all, &v)
+	if err != nil {
+		return nil, err
+	}
+	return sliceIter(v), nil
+}
+
+// ListItem contains the types that can be returned by list queries.
+// Use it with ListQueryOf() to get typed results.
+type ListItem interface {
+	Region | Kommune | Sogn | Retskreds | Politikreds | Opstillingskreds | Valglandsdel | Ejerlav | AdgangsAdresse | Adresse | Postnummer
+}
+
+// listTypeOf returns the list type name of T.
+func listTypeOf[T ListItem]() string {
+	var v T
+	switch any(v).(type) {
+	case Region:
+		return "regioner"
+	case Kommune:
+		return "kommuner"
+	case Sogn:
+		return "sogne"
+	case Retskreds:
+		return "retskredse"
+	case Politikreds:
+		return "politikredse"
+	case Opstillingskreds:
+		return "opstillingskredse"
+	case Valglandsdel:
+		return "valglandsdele"
+	case Ejerlav:
+		return "ejerlav"
+	case AdgangsAdresse:
+		return "adgangsadresser"
+	case Adresse:
+		return "adresser"
+	case Postnummer:
+		return "postnumre"
+	}
+	panic("unknown list type")
+}
+
+// TypedListQuery is a query for a specific list type, that returns typed results.
+// Use dawa.ListQueryOf[T](autocomplete bool) to create a new query.
+//
+// Example:
+//			// Get kommuner that start with "aa"
+//			items, err := dawa.ListQueryOf[dawa.Kommune](false).Q("aa*").All()
+//
+// See documentation at http://dawa.aws.dk/listerdok
+type TypedListQuery[T ListItem] struct {
+	queryGeoJSON
+}
+
+// ListQueryOf returns a query item for searching DAWA for the list type of T.
+// The list type is determined by T, for instance ListQueryOf[Region]() will search "regioner".
+//
+// See documentation at http://dawa.aws.dk/listerdok
+func ListQueryOf[T ListItem](autoComplete bool) *TypedListQuery[T] {
+	return ClientListQueryOf[T](DefaultClient, autoComplete)
+}
+
+// ClientListQueryOf returns a query like ListQueryOf, that will execute using the supplied client.
+func ClientListQueryOf[T ListItem](c *Client, autoComplete bool) *TypedListQuery[T] {
+	path := "/" + listTypeOf[T]()
+	if autoComplete {
+		path += "/autocomplete"
+	}
+	return &TypedListQuery[T]{queryGeoJSON: c.newQuery(path)}
+}
+
+// Q will add a parameter for 'q' to the TypedListQuery.
+//
+// Søgetekst. Der søges i kode og navn. Alle ord i søgeteksten skal matche. Wildcard * er tilladt i slutningen af hvert ord.
+//
+// See http://dawa.aws.dk/listerdok
+func (q *TypedListQuery[T]) Q(s string) *TypedListQuery[T] {
+	q.add(&textQuery{Name: "q", Values: []string{s}, Multi: true, Null: false})
+	return q
+}
+
+// Kode will add a parameter for 'kode' to the TypedListQuery.
+//
+// Kode for det der søges.
+func (q *TypedListQuery[T]) Kode(s ...string) *TypedListQuery[T] {
+	q.add(&textQuery{Name: "kode", Values: s, Multi: true, Null: false})
+	return q
+}
+
+// Navn will add a parameter for 'navn' to the TypedListQuery.
+//
+// Navn for det der søges.
+func (q *TypedListQuery[T]) Navn(s string) *TypedListQuery[T] {
+	q.add(&textQuery{Name: "navn", Values: []string{s}, Multi: true, Null: false})
+	return q
+}
+
+// NoFormat will disable extra whitespace. Always enabled when querying
+func (q *TypedListQuery[T]) NoFormat() *TypedListQuery[T] {
+	q.add(&textQuery{Name: "noformat", Multi: false, Null: true})
+	return q
+}
+
+// Iter will return an iterator that allows you to read the results
+// one by one.
+func (q TypedListQuery[T]) Iter() (*Iter[T], error) {
+	return q.IterContext(context.Background())
+}
+
+// IterContext will return an iterator like Iter().
+// If ctx is cancelled, the request is aborted and
+// the iterator will return the context error.
+func (q TypedListQuery[T]) IterContext(ctx context.Context) (*Iter[T], error) {
+	return iterQuery[T](ctx, q.query)
+}
+
+// All returns all results as an array.
+func (q TypedListQuery[T]) All() ([]T, error) {
+	return q.AllContext(context.Background())
+}
+
+// AllContext returns all results as an array like All().
+// If ctx is cancelled, the request is aborted and the context error is returned.
+func (q TypedListQuery[T]) AllContext(ctx context.Context) ([]T, error) {
+	return allQuery[T](ctx, q.query)
+}
+
+// First will return the first result from a query.
+//
+// Will return (nil, io.EOF) if there is no results.
+func (q TypedListQuery[T]) First() (*T, error) {
+	return q.FirstContext(context.Background())
+}
+
+// FirstContext will return the first result from a query like First().
+// If ctx is cancelled, the request is aborted and the context error is returned.
+func (q TypedListQuery[T]) FirstContext(ctx context.Context) (*T, error) {
+	return firstQuery[T](ctx, q.query)
+}
+
+// ReverseQueryOf will do a reverse location to item lookup for the list type of T.
+// See NewReverseQuery() for a description of the parameters.
+//
+// An iterator will be returned, but it will only contain zero or one values.
+func ReverseQueryOf[T ListItem](p Point) (*Iter[T], error) {
+	return ClientReverseQueryOfContext[T](context.Background(), DefaultClient, p)
+}
+
+// ReverseQueryOfContext will do a reverse lookup like ReverseQueryOf().
+// If ctx is cancelled, the request is aborted and the context error is returned.
+func ReverseQueryOfContext[T ListItem](ctx context.Context, p Point) (*Iter[T], error) {
+	return ClientReverseQueryOfContext[T](ctx, DefaultClient, p)
+}
+
+// ClientReverseQueryOf will do a reverse lookup like ReverseQueryOf, using the supplied client.
+func ClientReverseQueryOf[T ListItem](c *Client, p Point) (*Iter[T], error) {
+	return ClientReverseQueryOfContext[T](context.Background(), c, p)
+}
+
+// ClientReverseQueryOfContext will do a reverse lookup like ReverseQueryOfContext, using the supplied client.
+func ClientReverseQueryOfContext[T ListItem](ctx context.Context, c *Client, p Point) (*Iter[T], error) {
+	q, err := c.newReverseQuery(listTypeOf[T](), p)
 	if err != nil {
 		return nil, err
 	}
-	ret.a.Send(reflect.ValueOf(typ))
-	ret.a.Close()
-	ret.err = io.EOF
-	return ret, nil
+	return reverseQuery[T](ctx, q)
 }
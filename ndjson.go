@@ -0,0 +1,48 @@
+package dawa
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// ExportNDJSON will write all items from the iterator to w as newline delimited JSON,
+// with one JSON object per line.
+// The objects have the same format as read by the JSON importers, like ImportAdresserJSON.
+// The iterator is closed when done.
+//
+// Example:
+//			iter, _ := dawa.ImportAdresserCSV(in)
+//			err := dawa.ExportNDJSON(out, iter)
+func ExportNDJSON[T any](w io.Writer, iter *Iter[T]) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for a, err := range iter.All() {
+		if err != nil {
+			return err
+		}
+		if err := enc.Encode(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportListNDJSON will write all items from a list iterator to w as newline delimited JSON.
+// The iterator is closed when done.
+func ExportListNDJSON(w io.Writer, iter *ListIter) error {
+	defer iter.Close()
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for {
+		a, err := iter.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := enc.Encode(a); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package dawa
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportNDJSON(t *testing.T) {
+	iter, err := ImportAdresserCSV(strings.NewReader(csv_data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := ExportNDJSON(&buf, iter); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %d:\n%s", len(lines), buf.String())
+	}
+	var a Adresse
+	if err := json.Unmarshal([]byte(lines[0]), &a); err != nil {
+		t.Fatal(err)
+	}
+	if a.ID != "0a3f50b7-6545-32b8-e044-0003ba298018" || a.Adgangsadresse.Vejstykke.Navn != "A Hansensvej" || a.Adressebetegnelse != "A Hansensvej 6, Vråby, 6792 Rømø" {
+		t.Fatalf("Unexpected value: %+v", a)
+	}
+	if !a.Historik.Oprettet.Time().Equal(MustParseTime("2000-02-05T18:09:56.000").Time()) {
+		t.Fatalf("Unexpected time: %v", a.Historik.Oprettet.Time().Format(time.RFC3339))
+	}
+
+	// List iterators
+	liter, err := ImportListCSV("kommuner", strings.NewReader(kommuner_csv_data), CSVOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if err := ExportListNDJSON(&buf, liter); err != nil {
+		t.Fatal(err)
+	}
+	dec := json.NewDecoder(&buf)
+	var k Kommune
+	for _, kode := range []string{"0101", "0147"} {
+		if err := dec.Decode(&k); err != nil {
+			t.Fatal(err)
+		}
+		if k.Kode != kode || k.Regionskode != "1084" {
+			t.Fatalf("Unexpected value: %+v", k)
+		}
+	}
+	if dec.More() {
+		t.Fatal("Expected no more values")
+	}
+}
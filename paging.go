@@ -0,0 +1,128 @@
+package dawa
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// paging will make the query return results page by page.
+func (q *query) paging(pageSize, maxResults int) {
+	if pageSize <= 0 {
+		q.warnings = append(q.warnings, fmt.Errorf("Invalid page size %d", pageSize))
+		return
+	}
+	q.pageSize = pageSize
+	q.maxResults = maxResults
+}
+
+// pageQuery returns a query for the specified page, starting at 1.
+func (q query) pageQuery(page int) query {
+	p := q.clone()
+	p.set(&textQuery{Name: "side", Values: []string{strconv.Itoa(page)}})
+	p.set(&textQuery{Name: "per_side", Values: []string{strconv.Itoa(q.pageSize)}})
+	p.set(&textQuery{Name: "noformat", Null: true})
+	return p
+}
+
+// iterPages returns an iterator that will fetch results of the query page by page.
+func iterPages[T any](ctx context.Context, q query) (*Iter[T], error) {
+	return pagesIter(ctx, q, func(ctx context.Context, n int) (*Iter[T], error) {
+		resp, err := q.pageQuery(n).RequestContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		iter := importJSON[T](ctx, resp)
+		iter.AddCloser(resp)
+		return iter, nil
+	})
+}
+
+// pagesIter returns an iterator that will return the results of the pages returned by page.
+// A page is only requested when the consumer asks for an entry after all entries of the previous page.
+// page is called with a context that is cancelled when the iterator is closed.
+func pagesIter[T any](ctx context.Context, q query, page func(ctx context.Context, n int) (*Iter[T], error)) (*Iter[T], error) {
+	pctx, cancel := context.WithCancel(ctx)
+	// Request the first page, so errors are returned at once.
+	first, err := page(pctx, 1)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// Unbuffered, so following pages are only requested when needed.
+	ret := newIter[T](ctx, 0)
+	ret.want = make(chan struct{})
+	ret.AddCloser(cancelCloser(cancel))
+	fetch := func(n int) (*Iter[T], error) {
+		// Wait for the consumer to ask for the next entry.
+		select {
+		case <-ret.want:
+		case <-ret.done:
+			return nil, ErrIteratorClosed
+		case <-pctx.Done():
+			return nil, pctx.Err()
+		}
+		return page(pctx, n)
+	}
+	go func() {
+		defer cancel()
+		defer close(ret.a)
+		ret.err = ret.closedErr(paginate(first, q.pageSize, q.maxResults, fetch, func(v *T) bool {
+			return ret.send(*v)
+		}))
+	}()
+	return ret, nil
+}
+
+// cancelCloser will cancel a context when closed.
+type cancelCloser context.CancelFunc
+
+func (c cancelCloser) Close() error {
+	c()
+	return nil
+}
+
+// paginate will read all results from first, and fetch following pages
+// until a page with less than pageSize results is returned,
+// or maxResults have been sent, if maxResults > 0.
+// Results are passed to send. If send returns false, paginate will return ErrIteratorClosed.
+// When all results have been sent io.EOF is returned.
+func paginate[T any](first *Iter[T], pageSize, maxResults int, fetch func(page int) (*Iter[T], error), send func(*T) bool) error {
+	it := first
+	sent := 0
+	for page := 1; ; page++ {
+		if page > 1 {
+			var err error
+			it, err = fetch(page)
+			if err != nil {
+				return err
+			}
+		}
+		n := 0
+		for {
+			v, err := it.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				it.Close()
+				return err
+			}
+			n++
+			if !send(v) {
+				it.Close()
+				return ErrIteratorClosed
+			}
+			sent++
+			if maxResults > 0 && sent >= maxResults {
+				it.Close()
+				return io.EOF
+			}
+		}
+		it.Close()
+		if n < pageSize {
+			return io.EOF
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package dawa
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// pageServer serves 'total' adresser with ids "0", "1",... respecting 'side' and 'per_side'.
+func pageServer(total int) (*httptest.Server, func() []string) {
+	var mu sync.Mutex
+	var pages []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		side, _ := strconv.Atoi(r.URL.Query().Get("side"))
+		perSide, _ := strconv.Atoi(r.URL.Query().Get("per_side"))
+		mu.Lock()
+		pages = append(pages, r.URL.Query().Get("side"))
+		mu.Unlock()
+		var items []string
+		for i := (side - 1) * perSide; i < side*perSide && i < total; i++ {
+			items = append(items, fmt.Sprintf(`{"id":"%d"}`, i))
+		}
+		w.Write([]byte("[" + strings.Join(items, ",") + "]"))
+	}))
+	return ts, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), pages...)
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	tests := []struct {
+		total, pageSize, max int
+		want                 int
+		pages                []string
+	}{
+		{total: 25, pageSize: 10, want: 25, pages: []string{"1", "2", "3"}},
+		{total: 20, pageSize: 10, want: 20, pages: []string{"1", "2", "3"}},
+		{total: 25, pageSize: 10, max: 15, want: 15, pages: []string{"1", "2"}},
+		{total: 0, pageSize: 10, want: 0, pages: []string{"1"}},
+	}
+	for _, test := range tests {
+		ts, pages := pageServer(test.total)
+		c := &Client{BaseURL: ts.URL}
+		all, err := c.NewAdresseQuery().Side(5).Paginate(test.pageSize, test.max).All()
+		ts.Close()
+		if err != nil {
+			t.Fatalf("All: %v", err)
+		}
+		if len(all) != test.want {
+			t.Fatalf("%+v: expected %d results, got %d", test, test.want, len(all))
+		}
+		for i, a := range all {
+			if a.ID != strconv.Itoa(i) {
+				t.Fatalf("%+v: result %d had id %s", test, i, a.ID)
+			}
+		}
+		if got := pages(); strings.Join(got, ",") != strings.Join(test.pages, ",") {
+			t.Fatalf("%+v: expected pages %v, got %v", test, test.pages, got)
+		}
+	}
+}
+
+func TestPaginateLazy(t *testing.T) {
+	ts, pages := pageServer(100)
+	defer ts.Close()
+
+	c := &Client{BaseURL: ts.URL}
+	iter, err := c.NewAdgangsAdresseQuery().Paginate(10, 0).Iter()
+	if err != nil {
+		t.Fatalf("Iter: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		if _, err := iter.Next(); err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+	}
+	iter.Close()
+	if got := pages(); len(got) != 1 {
+		t.Fatalf("Expected 1 page to be requested, got %v", got)
+	}
+}
+
+func TestPaginateCloseStalled(t *testing.T) {
+	var mu sync.Mutex
+	var pages []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		side := r.URL.Query().Get("side")
+		mu.Lock()
+		pages = append(pages, side)
+		mu.Unlock()
+		if side != "1" {
+			// Stall until the request is cancelled.
+			select {
+			case <-r.Context().Done():
+			case <-time.After(10 * time.Second):
+			}
+			return
+		}
+		var items []string
+		for i := 0; i < 10; i++ {
+			items = append(items, fmt.Sprintf(`{"id":"%d"}`, i))
+		}
+		w.Write([]byte("[" + strings.Join(items, ",") + "]"))
+	}))
+	defer ts.Close()
+
+	c := &Client{BaseURL: ts.URL}
+	iter, err := c.NewAdresseQuery().Paginate(10, 0).Iter()
+	if err != nil {
+		t.Fatalf("Iter: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		if _, err := iter.Next(); err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+	}
+	// The second page is not requested before it is needed.
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	n := len(pages)
+	mu.Unlock()
+	if n != 1 {
+		t.Fatalf("Expected 1 page to be requested, got %d", n)
+	}
+
+	nextErr := make(chan error, 1)
+	go func() {
+		_, err := iter.Next()
+		nextErr <- err
+	}()
+	// Wait for the second page to be requested.
+	for {
+		mu.Lock()
+		n := len(pages)
+		mu.Unlock()
+		if n == 2 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	closed := make(chan struct{})
+	go func() {
+		iter.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close blocked on the stalled page request")
+	}
+	if err := <-nextErr; err == nil || err == io.EOF {
+		t.Fatalf("Expected an error, got %v", err)
+	}
+}
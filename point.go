@@ -0,0 +1,248 @@
+package dawa
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// SRID identifies a koordinatsystem by its EPSG code.
+type SRID int
+
+// The koordinatsystemer supported by DAWA.
+const (
+	WGS84       SRID = 4326  // WGS84/geografisk. X is længde and Y is bredde in degrees.
+	ETRS89UTM32 SRID = 25832 // ETRS89/UTM zone 32N. X is øst and Y is nord in meters.
+)
+
+// String returns the EPSG code of the SRID.
+func (s SRID) String() string {
+	return strconv.Itoa(int(s))
+}
+
+// Valid returns true if the SRID is supported.
+func (s SRID) Valid() bool {
+	return s == WGS84 || s == ETRS89UTM32
+}
+
+// Point is a koordinat in a koordinatsystem.
+// The zero SRID is treated as WGS84, which is the DAWA default.
+type Point struct {
+	X    float64 // Længde or øst.
+	Y    float64 // Bredde or nord.
+	SRID SRID
+}
+
+// WGS84Point returns a WGS84 point with the længde and bredde in degrees.
+func WGS84Point(længde, bredde float64) Point {
+	return Point{X: længde, Y: bredde, SRID: WGS84}
+}
+
+// ETRS89Point returns an ETRS89/UTM32 point with the øst and nord values in meters.
+func ETRS89Point(øst, nord float64) Point {
+	return Point{X: øst, Y: nord, SRID: ETRS89UTM32}
+}
+
+// String returns the point as "x,y", as used in DAWA parameters.
+func (p Point) String() string {
+	return strconv.FormatFloat(p.X, 'f', -1, 64) + "," + strconv.FormatFloat(p.Y, 'f', -1, 64)
+}
+
+// srid returns the SRID of the point, with WGS84 as the default.
+func (p Point) srid() SRID {
+	if p.SRID == 0 {
+		return WGS84
+	}
+	return p.SRID
+}
+
+// Transform returns the point in the koordinatsystem.
+// An error is returned if either SRID isn't supported.
+//
+// ETRS89 and WGS84 are treated as the same datum, which is accurate to
+// below a meter in Denmark, and is what DAWA does.
+// The transverse Mercator projection is accurate to well below a millimeter.
+func (p Point) Transform(to SRID) (Point, error) {
+	from := p.srid()
+	if to == 0 {
+		to = WGS84
+	}
+	switch {
+	case !from.Valid():
+		return p, fmt.Errorf("dawa: unsupported srid %d", from)
+	case !to.Valid():
+		return p, fmt.Errorf("dawa: unsupported srid %d", to)
+	case from == to:
+		return Point{X: p.X, Y: p.Y, SRID: to}, nil
+	case to == ETRS89UTM32:
+		øst, nord := utm32Forward(p.X, p.Y)
+		return ETRS89Point(øst, nord), nil
+	default:
+		længde, bredde := utm32Inverse(p.X, p.Y)
+		return WGS84Point(længde, bredde), nil
+	}
+}
+
+// Parameters of UTM zone 32N on the GRS80 ellipsoid.
+const (
+	utmA        = 6378137.0
+	utmF        = 1 / 298.257222101
+	utmK0       = 0.9996
+	utmE0       = 500000.0
+	utm32Lambda = 9 * math.Pi / 180
+)
+
+// Series coefficients of the Krüger projection to 6th order in n, from Karney (2011).
+var utmE, utmAA, utmAlpha, utmBeta = func() (e, aa float64, alpha, beta [6]float64) {
+	n := utmF / (2 - utmF)
+	n2, n3, n4, n5, n6 := n*n, n*n*n, n*n*n*n, n*n*n*n*n, n*n*n*n*n*n
+	e = math.Sqrt(utmF * (2 - utmF))
+	aa = utmA / (1 + n) * (1 + n2/4 + n4/64 + n6/256)
+	alpha = [6]float64{
+		n/2 - 2*n2/3 + 5*n3/16 + 41*n4/180 - 127*n5/288 + 7891*n6/37800,
+		13*n2/48 - 3*n3/5 + 557*n4/1440 + 281*n5/630 - 1983433*n6/1935360,
+		61*n3/240 - 103*n4/140 + 15061*n5/26880 + 167603*n6/181440,
+		49561*n4/161280 - 179*n5/168 + 6601661*n6/7257600,
+		34729*n5/80640 - 3418889*n6/1995840,
+		212378941 * n6 / 319334400,
+	}
+	beta = [6]float64{
+		n/2 - 2*n2/3 + 37*n3/96 - n4/360 - 81*n5/512 + 96199*n6/604800,
+		n2/48 + n3/15 - 437*n4/1440 + 46*n5/105 - 1118711*n6/3870720,
+		17*n3/480 - 37*n4/840 - 209*n5/4480 + 5569*n6/90720,
+		4397*n4/161280 - 11*n5/504 - 830251*n6/7257600,
+		4583*n5/161280 - 108847*n6/3991680,
+		20648693 * n6 / 638668800,
+	}
+	return
+}()
+
+// conformal returns tan of the conformal latitude for tau, tan of the latitude.
+func conformal(tau float64) float64 {
+	sigma := math.Sinh(utmE * math.Atanh(utmE*tau/math.Hypot(1, tau)))
+	return tau*math.Hypot(1, sigma) - sigma*math.Hypot(1, tau)
+}
+
+// utm32Forward projects længde and bredde in degrees to øst and nord in UTM zone 32.
+func utm32Forward(længde, bredde float64) (øst, nord float64) {
+	lambda := længde*math.Pi/180 - utm32Lambda
+	t := conformal(math.Tan(bredde * math.Pi / 180))
+	xi := math.Atan2(t, math.Cos(lambda))
+	eta := math.Asinh(math.Sin(lambda) / math.Hypot(t, math.Cos(lambda)))
+	e, n := eta, xi
+	for j, a := range utmAlpha {
+		k := 2 * float64(j+1)
+		e += a * math.Cos(k*xi) * math.Sinh(k*eta)
+		n += a * math.Sin(k*xi) * math.Cosh(k*eta)
+	}
+	return utmE0 + utmK0*utmAA*e, utmK0 * utmAA * n
+}
+
+// utm32Inverse converts øst and nord in UTM zone 32 to længde and bredde in degrees.
+func utm32Inverse(øst, nord float64) (længde, bredde float64) {
+	xi := nord / (utmK0 * utmAA)
+	eta := (øst - utmE0) / (utmK0 * utmAA)
+	xi2, eta2 := xi, eta
+	for j, b := range utmBeta {
+		k := 2 * float64(j+1)
+		xi2 -= b * math.Sin(k*xi) * math.Cosh(k*eta)
+		eta2 -= b * math.Cos(k*xi) * math.Sinh(k*eta)
+	}
+	// Find the latitude from the conformal latitude with Newton's method.
+	t := math.Sin(xi2) / math.Hypot(math.Sinh(eta2), math.Cos(xi2))
+	tau := t
+	e2 := utmE * utmE
+	for i := 0; i < 5; i++ {
+		ti := conformal(tau)
+		d := (t - ti) / math.Hypot(1, ti) * (1 + (1-e2)*tau*tau) / ((1 - e2) * math.Hypot(1, tau))
+		tau += d
+		if math.Abs(d) < 1e-14 {
+			break
+		}
+	}
+	lambda := utm32Lambda + math.Atan2(math.Sinh(eta2), math.Cos(xi2))
+	return lambda * 180 / math.Pi, math.Atan(tau) * 180 / math.Pi
+}
+
+// WGS84 returns the adgangspunkt as a WGS84 point.
+// If only the ETRS89 koordinater are set, they are transformed.
+// ok is false if no koordinater are set.
+func (a Adgangspunkt) WGS84() (p Point, ok bool) {
+	if len(a.Koordinater) >= 2 {
+		return WGS84Point(a.Koordinater[0], a.Koordinater[1]), true
+	}
+	if len(a.Etrs89Koordinater) >= 2 {
+		p, _ = ETRS89Point(a.Etrs89Koordinater[0], a.Etrs89Koordinater[1]).Transform(WGS84)
+		return p, true
+	}
+	return Point{}, false
+}
+
+// ETRS89 returns the adgangspunkt as an ETRS89/UTM32 point.
+// If only the WGS84 koordinater are set, they are transformed.
+// ok is false if no koordinater are set.
+func (a Adgangspunkt) ETRS89() (p Point, ok bool) {
+	if len(a.Etrs89Koordinater) >= 2 {
+		return ETRS89Point(a.Etrs89Koordinater[0], a.Etrs89Koordinater[1]), true
+	}
+	if len(a.Koordinater) >= 2 {
+		p, _ = WGS84Point(a.Koordinater[0], a.Koordinater[1]).Transform(ETRS89UTM32)
+		return p, true
+	}
+	return Point{}, false
+}
+
+// addSrid will add the srid parameter to the query.
+// A warning is added if the SRID isn't supported, or if the query already has a different SRID.
+// If polygon or cirkel has already been added in another SRID, the query is invalidated,
+// since they would be read in the wrong koordinatsystem.
+func (q *query) addSrid(s SRID) {
+	if !s.Valid() {
+		q.warnings = append(q.warnings, fmt.Errorf("dawa: unsupported srid %d", s))
+	}
+	cur := q.sridOf(Point{})
+	if _, ok := q.params["srid"]; ok && cur == s {
+		return
+	}
+	_, polygon := q.params["polygon"]
+	_, cirkel := q.params["cirkel"]
+	if (polygon || cirkel) && cur != s {
+		q.invalidate(fmt.Errorf("dawa: srid %d must be set before polygon and cirkel", s))
+		return
+	}
+	q.add(&textQuery{Name: "srid", Values: []string{s.String()}, Multi: false, Null: false})
+}
+
+// sridOf returns the SRID geospatial parameters are sent in.
+// This is the SRID of the query if set, otherwise the SRID of p.
+func (q *query) sridOf(p Point) SRID {
+	if v, ok := q.params["srid"]; ok {
+		if s, err := strconv.Atoi(firstValue(v.AllValues())); err == nil && SRID(s).Valid() {
+			return SRID(s)
+		}
+	}
+	return p.srid()
+}
+
+// geoSrid returns the SRID geospatial parameters should be sent in, with p as the first point.
+// If polygon or cirkel has already been added, it is the SRID they were sent in.
+// Otherwise, if the query has no SRID, the SRID of p is added, unless it is the default.
+func (q *query) geoSrid(p Point) SRID {
+	_, polygon := q.params["polygon"]
+	_, cirkel := q.params["cirkel"]
+	if polygon || cirkel {
+		return q.sridOf(Point{})
+	}
+	s := q.sridOf(p)
+	if s != WGS84 {
+		q.addSrid(s)
+	}
+	return s
+}
+
+func firstValue(v []string) string {
+	if len(v) == 0 {
+		return ""
+	}
+	return v[0]
+}
@@ -0,0 +1,157 @@
+package dawa
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestPointTransform(t *testing.T) {
+	// Values from the CSV test data.
+	tests := []struct {
+		øst, nord, længde, bredde float64
+	}{
+		{470620, 6105713, 8.53959543878291, 55.0972751504817},
+		{470587, 6105811, 8.5390681928166, 55.0981538216665},
+		{723743.16, 6175322.16, 12.5582458296225, 55.6720594006065},
+		{723928.98, 6175195.49, 12.5610912697286, 55.6708378041398},
+	}
+	for _, test := range tests {
+		e, err := WGS84Point(test.længde, test.bredde).Transform(ETRS89UTM32)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// DAWA rounds the ETRS89 koordinater to centimeters.
+		if e.SRID != ETRS89UTM32 || math.Abs(e.X-test.øst) > 0.01 || math.Abs(e.Y-test.nord) > 0.01 {
+			t.Errorf("Got %+v, expected %v, %v", e, test.øst, test.nord)
+		}
+		w, err := ETRS89Point(test.øst, test.nord).Transform(WGS84)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w.SRID != WGS84 || math.Abs(w.X-test.længde) > 1e-7 || math.Abs(w.Y-test.bredde) > 1e-7 {
+			t.Errorf("Got %+v, expected %v, %v", w, test.længde, test.bredde)
+		}
+		// Round trip.
+		back, _ := w.Transform(ETRS89UTM32)
+		if math.Abs(back.X-test.øst) > 1e-6 || math.Abs(back.Y-test.nord) > 1e-6 {
+			t.Errorf("Round trip: got %+v, expected %v, %v", back, test.øst, test.nord)
+		}
+	}
+
+	// The zero SRID is WGS84.
+	p, err := Point{X: 9, Y: 0}.Transform(ETRS89UTM32)
+	if err != nil || math.Abs(p.X-500000) > 1e-6 || math.Abs(p.Y) > 1e-6 {
+		t.Fatalf("Unexpected result: %+v, %v", p, err)
+	}
+	p, err = Point{X: 1, Y: 2}.Transform(0)
+	if err != nil || p != WGS84Point(1, 2) {
+		t.Fatalf("Unexpected result: %+v, %v", p, err)
+	}
+
+	for _, p := range []Point{{X: 1, Y: 2, SRID: 3857}, WGS84Point(1, 2)} {
+		if _, err := p.Transform(3857); err == nil || !strings.Contains(err.Error(), "unsupported srid 3857") {
+			t.Errorf("Expected error, got %v", err)
+		}
+	}
+}
+
+func TestAdgangspunktPoints(t *testing.T) {
+	var a Adgangspunkt
+	if _, ok := a.WGS84(); ok {
+		t.Fatal("Expected no WGS84 point")
+	}
+	if _, ok := a.ETRS89(); ok {
+		t.Fatal("Expected no ETRS89 point")
+	}
+
+	a.Etrs89Koordinater = []float64{470620, 6105713}
+	w, ok := a.WGS84()
+	if !ok || w.SRID != WGS84 || math.Abs(w.X-8.5396) > 1e-4 || math.Abs(w.Y-55.0973) > 1e-4 {
+		t.Fatalf("Unexpected WGS84 point: %+v", w)
+	}
+	a.Koordinater = []float64{8, 55}
+	if w, _ := a.WGS84(); w != WGS84Point(8, 55) {
+		t.Fatalf("Unexpected WGS84 point: %+v", w)
+	}
+	if e, _ := a.ETRS89(); e != ETRS89Point(470620, 6105713) {
+		t.Fatalf("Unexpected ETRS89 point: %+v", e)
+	}
+}
+
+func TestImportCSVKoordinater(t *testing.T) {
+	// Without the WGS84 columns, they are transformed from ETRS89.
+	data := strings.Replace(adgangs_csv_data, "wgs84koordinat_bredde,wgs84koordinat_længde", "x,y", 1)
+	iter, err := ImportAdgangsAdresserCSV(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := iter.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	iter.Close()
+	k := a.Adgangspunkt.Koordinater
+	if len(k) != 2 || math.Abs(k[0]-12.5582458296225) > 1e-7 || math.Abs(k[1]-55.6720594006065) > 1e-7 {
+		t.Fatalf("Unexpected koordinater: %v", k)
+	}
+}
+
+func TestQueryPoints(t *testing.T) {
+	ring := []Point{ETRS89Point(723700, 6175300), ETRS89Point(723800, 6175300), ETRS89Point(723800, 6175400), ETRS89Point(723700, 6175300)}
+	q := NewAdgangsAdresseQuery().Polygon(ring)
+	expect := DefaultHost + "/adgangsadresser?srid=25832&polygon=%5B%5B%5B723700%2C6175300%5D%2C%5B723800%2C6175300%5D%2C%5B723800%2C6175400%5D%2C%5B723700%2C6175300%5D%5D%5D"
+	if got := q.URL(); got != expect || q.HasWarnings() {
+		t.Fatalf("Got %s, expected %s, warnings %v", got, expect, q.Warnings())
+	}
+
+	// Points are transformed to the SRID of the query.
+	q = NewAdgangsAdresseQuery().Srid(ETRS89UTM32).Cirkel(WGS84Point(9, 0), 10)
+	expect = DefaultHost + "/adgangsadresser?srid=25832&cirkel=500000%2C0%2C10"
+	if got := q.URL(); got != expect || q.HasWarnings() {
+		t.Fatalf("Got %s, expected %s, warnings %v", got, expect, q.Warnings())
+	}
+
+	// Later geometry is transformed to the SRID of the geometry already added.
+	q = NewAdgangsAdresseQuery().Cirkel(WGS84Point(12, 55), 10).Polygon(ring)
+	wgs := make([]Point, len(ring))
+	for i, p := range ring {
+		wgs[i], _ = p.Transform(WGS84)
+	}
+	expect = NewAdgangsAdresseQuery().Cirkel(WGS84Point(12, 55), 10).Polygon(wgs).URL()
+	if got := q.URL(); got != expect || q.HasWarnings() || strings.Contains(got, "srid") {
+		t.Fatalf("Got %s, expected %s, warnings %v", got, expect, q.Warnings())
+	}
+	q = NewAdgangsAdresseQuery().Polygon(ring).Cirkel(WGS84Point(12.56, 55.67), 10)
+	if got := q.URL(); !strings.Contains(got, "srid=25832") || !strings.Contains(got, "cirkel=723865.") || q.HasWarnings() {
+		t.Fatalf("Got %s, warnings %v", got, q.Warnings())
+	}
+
+	// Srid after a cirkel in another SRID fails the query.
+	q = NewAdgangsAdresseQuery().Cirkel(WGS84Point(12, 55), 10).Srid(ETRS89UTM32)
+	if w := q.Warnings(); len(w) != 1 || !strings.Contains(w[0].Error(), "must be set before") || strings.Contains(q.URL(), "srid") {
+		t.Fatalf("Unexpected warnings: %v, %s", w, q.URL())
+	}
+	if _, err := q.All(); err == nil || !strings.Contains(err.Error(), "must be set before") {
+		t.Fatalf("Expected error, got %v", err)
+	}
+	q = NewAdgangsAdresseQuery().Srid(3857)
+	if w := q.Warnings(); len(w) != 1 || !strings.Contains(w[0].Error(), "unsupported srid") {
+		t.Fatalf("Unexpected warnings: %v", w)
+	}
+	q = NewAdgangsAdresseQuery().Polygon()
+	if w := q.Warnings(); len(w) != 1 || !strings.Contains(w[0].Error(), "no rings") {
+		t.Fatalf("Unexpected warnings: %v", w)
+	}
+
+	rq, err := DefaultClient.newReverseQuery("regioner", ETRS89Point(723743.16, 6175322.16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, expect := rq.URL(), DefaultHost+"/regioner/reverse?x=723743.16&y=6175322.16&srid=25832"; got != expect {
+		t.Fatalf("Got %s, expected %s", got, expect)
+	}
+	if _, err := ReverseQueryOf[Region](Point{X: 1, Y: 2, SRID: 3857}); err == nil {
+		t.Fatal("Expected error on unsupported srid")
+	}
+}
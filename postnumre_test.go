@@ -128,3 +128,27 @@ func TestImportPostnumreJSON(t *testing.T) {
 		t.Fatalf("ImportPostnumreJSON: Expected io.EOF, got:%v", err)
 	}
 }
+
+func TestImportPostnumreCSV(t *testing.T) {
+	data := "nr,navn,stormodtager,href\n9981,Jerup,false,http://dawa.aws.dk/postnumre/9981\n9982,Ålbæk,false,\n"
+	expect := []Postnummer{
+		{Href: "http://dawa.aws.dk/postnumre/9981", Navn: "Jerup", Nr: "9981"},
+		{Navn: "Ålbæk", Nr: "9982"},
+	}
+	iter, err := ImportCSV[Postnummer](bytes.NewBufferString(data), CSVOptions{})
+	if err != nil {
+		t.Fatalf("ImportPostnumreCSV: %v", err)
+	}
+	for i, e := range expect {
+		item, err := iter.Next()
+		if err != nil {
+			t.Fatalf("ImportPostnumreCSV, iter.Next(): %v", err)
+		}
+		if !reflect.DeepEqual(*item, e) {
+			t.Fatalf("ImportPostnumreCSV %d, value mismatch.\nGot:\n%#v\nExpected:\n%#v\n", i, *item, e)
+		}
+	}
+	if _, err := iter.Next(); err != io.EOF {
+		t.Fatalf("ImportPostnumreCSV: Expected io.EOF, got:%v", err)
+	}
+}
@@ -1,8 +1,8 @@
 package dawa
 
 import (
+	"context"
 	"fmt"
-	"io"
 )
 
 // PostnrQuery is a new query for 'postnummer' objects for searching DAWA.
@@ -23,14 +23,14 @@ type PostnrQuery struct {
 //
 // See documentation at http://dawa.aws.dk/adgangsadressedok#adressesoegning
 func NewPostnrQuery() *PostnrQuery {
-	return &PostnrQuery{queryGeoJSON: queryGeoJSON{query: query{host: DefaultHost, path: "/postnumre"}}}
+	return DefaultClient.NewPostnrQuery()
 }
 
 // NewPostnrCompleteQuery returns a new autocomplete query for 'postnummer' objects for searching DAWA.
 //
 // See documentation at http://dawa.aws.dk/adgangsadressedok#adressesoegning
 func NewPostnrComplete() *PostnrQuery {
-	return &PostnrQuery{queryGeoJSON: queryGeoJSON{query: query{host: DefaultHost, path: "/postnumre/autocomplete"}}}
+	return DefaultClient.NewPostnrComplete()
 }
 
 // GetPostnr will return a single Postnummer with the specified ID.
@@ -61,46 +61,25 @@ func GetPostnr(id string) (*Postnummer, error) {
 //			}
 //		}
 func (q PostnrQuery) Iter() (*PostnummerIter, error) {
-	resp, err := q.NoFormat().Request()
-	if err != nil {
-		return nil, err
-	}
+	return q.IterContext(context.Background())
+}
 
-	iter, err := ImportPostnumreJSON(resp)
-	if err != nil {
-		return nil, err
-	}
-	iter.AddCloser(resp)
-	return iter, nil
+// IterContext will return an iterator like Iter().
+// If ctx is cancelled, the request is aborted and
+// the iterator will return the context error.
+func (q PostnrQuery) IterContext(ctx context.Context) (*PostnummerIter, error) {
+	return iterQuery[Postnummer](ctx, q.query)
 }
 
 // All returns all results as an array.
 func (q PostnrQuery) All() ([]Postnummer, error) {
-	resp, err := q.NoFormat().Request()
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Close()
-
-	ret := make([]Postnummer, 0)
-	iter, err := ImportPostnumreJSON(resp)
-	if err != nil {
-		return nil, err
-	}
-
-	for {
-		a, err := iter.Next()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return nil, err
-		}
-		if a != nil {
-			ret = append(ret, *a)
-		}
-	}
-	return ret, nil
+	return q.AllContext(context.Background())
+}
+
+// AllContext returns all results as an array like All().
+// If ctx is cancelled, the request is aborted and the context error is returned.
+func (q PostnrQuery) AllContext(ctx context.Context) ([]Postnummer, error) {
+	return allQuery[Postnummer](ctx, q.query)
 }
 
 // First will return the first result from a query.
@@ -108,22 +87,35 @@ func (q PostnrQuery) All() ([]Postnummer, error) {
 //
 // Will return (nil, io.EOF) if there is no results.
 func (q PostnrQuery) First() (*Postnummer, error) {
-	resp, err := q.NoFormat().Request()
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Close()
-
-	iter, err := ImportPostnumreJSON(resp)
-	if err != nil {
-		return nil, err
-	}
-
-	a, err := iter.Next()
-	if err != nil {
-		return nil, err
-	}
-	return a, nil
+	return q.FirstContext(context.Background())
+}
+
+// FirstContext will return the first result from a query like First().
+// If ctx is cancelled, the request is aborted and the context error is returned.
+func (q PostnrQuery) FirstContext(ctx context.Context) (*Postnummer, error) {
+	return firstQuery[Postnummer](ctx, q.query)
+}
+
+// GeoJSONTyped will return the results as GeoJSON features with typed properties and geometry.
+// The features are decoded as they are read, so large results are not kept in memory.
+// Close the iterator if you stop reading before all features have been returned.
+func (q PostnrQuery) GeoJSONTyped() (*Iter[Feature[Postnummer]], error) {
+	return q.GeoJSONTypedContext(context.Background())
+}
+
+// GeoJSONTypedContext will return the features like GeoJSONTyped().
+// If ctx is cancelled, the request is aborted and
+// the iterator will return the context error.
+func (q PostnrQuery) GeoJSONTypedContext(ctx context.Context) (*Iter[Feature[Postnummer]], error) {
+	return q.GeoJSONTypedOptions(ctx, CSVOptions{})
+}
+
+// GeoJSONTypedOptions will return the features like GeoJSONTypedContext().
+// The properties are parsed with the supplied options, like ImportCSV.
+// Properties that cannot be parsed stop the iterator with an *ImportError,
+// unless opts.OnError skips the feature.
+func (q PostnrQuery) GeoJSONTypedOptions(ctx context.Context, opts CSVOptions) (*Iter[Feature[Postnummer]], error) {
+	return geoJSONQuery[Postnummer](ctx, q.query, opts)
 }
 
 // Nr will add a parameter for 'nr' to the PostnrQuery.
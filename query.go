@@ -1,12 +1,12 @@
 package dawa
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/kpawlik/geojson"
 	"io"
 	"io/ioutil"
-	"net/http"
 	"net/url"
 )
 
@@ -23,11 +23,16 @@ type parameter interface {
 
 // A generic query structure
 type query struct {
+	client   *Client
 	host     string
 	path     string
 	params   map[string]parameter
 	keys     []string // Keys in the order they were added
 	warnings []error
+	err      error // If set, the query is invalid and will return this error instead of being sent.
+
+	pageSize   int // If > 0 results are fetched page by page.
+	maxResults int // If > 0 the maximum number of results returned when paginating.
 }
 
 type queryGeoJSON struct {
@@ -72,9 +77,38 @@ func (q *query) add(p parameter) {
 	q.params[key] = p
 }
 
+// set will add a parameter, replacing any existing value of the key.
+func (q *query) set(p parameter) {
+	if _, ok := q.params[p.Key()]; ok {
+		q.params[p.Key()] = p
+		return
+	}
+	q.add(p)
+}
+
+// clone returns a copy of the query, that can be modified without affecting q.
+func (q query) clone() query {
+	c := q
+	c.params = make(map[string]parameter, len(q.params))
+	for k, v := range q.params {
+		c.params[k] = v
+	}
+	c.keys = append([]string(nil), q.keys...)
+	c.warnings = append([]error(nil), q.warnings...)
+	return c
+}
+
+// getClient returns the client the query should be executed with.
+func (q query) getClient() *Client {
+	if q.client != nil {
+		return q.client
+	}
+	return DefaultClient
+}
+
 // WithHost allows overriding the host for this query.
 //
-// The default value is http://dawa.aws.dk
+// The default value is the BaseURL of the client, or http://dawa.aws.dk
 func (q *query) WithHost(s string) {
 	q.host = s
 }
@@ -170,8 +204,19 @@ func (r RequestError) Error() string {
 // this is returned.
 // In some cases the error will be a RequestError type.
 func (q query) Request() (io.ReadCloser, error) {
+	return q.RequestContext(context.Background())
+}
+
+// RequestContext performs the Request like Request(), but will abort
+// the request if ctx is cancelled. Reads from the returned
+// response will fail after ctx has been cancelled.
+// If the query has invalid geometry, the first geometry warning is returned and nothing is sent.
+func (q query) RequestContext(ctx context.Context) (io.ReadCloser, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
 	url := q.URL()
-	resp, err := http.Get(q.URL())
+	resp, err := q.getClient().get(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -194,9 +239,20 @@ func (q query) Request() (io.ReadCloser, error) {
 // this is returned.
 // In some cases the error will be a RequestError type.
 func (q queryGeoJSON) GeoJSON() (*geojson.FeatureCollection, error) {
+	return q.GeoJSONContext(context.Background())
+}
+
+// GeoJSONContext performs the Request like GeoJSON(), but will abort
+// the request if ctx is cancelled.
+func (q queryGeoJSON) GeoJSONContext(ctx context.Context) (*geojson.FeatureCollection, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	// The params are shared with the caller, so add format to a copy.
+	q.query = q.query.clone()
 	q.Add("format", "geojson")
 	url := q.URL()
-	resp, err := http.Get(q.URL())
+	resp, err := q.getClient().get(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -219,3 +275,56 @@ func (q queryGeoJSON) GeoJSON() (*geojson.FeatureCollection, error) {
 	}
 	return &fc, nil
 }
+
+// iterQuery will execute the query, and return an iterator
+// that will decode the results as T.
+func iterQuery[T any](ctx context.Context, q query) (*Iter[T], error) {
+	if q.pageSize > 0 {
+		return iterPages[T](ctx, q)
+	}
+	q = q.clone()
+	q.set(&textQuery{Name: "noformat", Null: true})
+	resp, err := q.RequestContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	iter := importJSON[T](ctx, resp)
+	iter.AddCloser(resp)
+	return iter, nil
+}
+
+// allQuery will execute the query and return all results as an array.
+func allQuery[T any](ctx context.Context, q query) ([]T, error) {
+	iter, err := iterQuery[T](ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	ret := make([]T, 0)
+	for {
+		a, err := iter.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if a != nil {
+			ret = append(ret, *a)
+		}
+	}
+	return ret, nil
+}
+
+// firstQuery will execute the query and return the first result.
+// Will return (nil, io.EOF) if there is no results.
+func firstQuery[T any](ctx context.Context, q query) (*T, error) {
+	iter, err := iterQuery[T](ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	// Stop decoding remaining results
+	defer iter.Close()
+	return iter.Next()
+}
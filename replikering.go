@@ -0,0 +1,439 @@
+package dawa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"time"
+)
+
+// Operations for replication events.
+const (
+	OperationInsert = "insert"
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+)
+
+// Sekvensnummer contains the sequence number of the latest replication event.
+type Sekvensnummer struct {
+	Sekvensnummer int64     `json:"sekvensnummer"` // Sekvensnummeret for den seneste hændelse.
+	Tidspunkt     time.Time `json:"tidspunkt"`     // Tidspunktet for den seneste hændelse.
+}
+
+// HaendelseData contains the types that can be replicated.
+type HaendelseData interface {
+	Adresse | AdgangsAdresse | Postnummer | Vejstykke
+}
+
+// Haendelse is a replication event.
+//
+// The data is delivered by DAWA in a flat replication format.
+// Only fields that are present in the replication format are filled
+// into Data, so references only contain the key, for instance
+// Vejstykke.Kode and Kommune.Kode, not the names.
+//
+// The ETRS89 koordinater of an adgangsadresse are filled into
+// Adgangspunkt.Etrs89Koordinater, and Adgangspunkt.Koordinater is
+// set to the same point transformed to WGS84.
+//
+// Fields of the replication format without a field in Data are not kept.
+// For adresser these are kilde, esdhreference and journalnummer.
+// For adgangsadresser these are husnummerkilde, højde, esdhreference,
+// journalnummer and the geometry of the husnummer and vejpunkt.
+type Haendelse[T HaendelseData] struct {
+	Operation     string    // Hvilken type operation hændelsen vedrører: insert, update eller delete.
+	Tidspunkt     time.Time // Tidspunktet hvor hændelsen blev indlæst af DAWA.
+	Sekvensnummer int64     // Hændelsens unikke sekvensnummer.
+	Data          T         // Objektet efter hændelsen. Ved delete er det objektet før sletning.
+}
+
+// AdresseHaendelse is a replication event for an Adresse.
+type AdresseHaendelse = Haendelse[Adresse]
+
+// AdgangsAdresseHaendelse is a replication event for an AdgangsAdresse.
+type AdgangsAdresseHaendelse = Haendelse[AdgangsAdresse]
+
+// PostnummerHaendelse is a replication event for a Postnummer.
+type PostnummerHaendelse = Haendelse[Postnummer]
+
+// VejstykkeHaendelse is a replication event for a Vejstykke.
+type VejstykkeHaendelse = Haendelse[Vejstykke]
+
+// replikeringAdresse is the replication format of an adresse.
+type replikeringAdresse struct {
+	ID               string  `json:"id"`
+	Status           int     `json:"status"`
+	Oprettet         AwsTime `json:"oprettet"`
+	Ændret           AwsTime `json:"ændret"`
+	AdgangsadresseID string  `json:"adgangsadresseid"`
+	Etage            string  `json:"etage"`
+	Dør              string  `json:"dør"`
+}
+
+// replikeringAdgangsAdresse is the replication format of an adgangsadresse.
+type replikeringAdgangsAdresse struct {
+	ID                       string   `json:"id"`
+	Status                   int      `json:"status"`
+	Oprettet                 AwsTime  `json:"oprettet"`
+	Ændret                   AwsTime  `json:"ændret"`
+	Kommunekode              string   `json:"kommunekode"`
+	Vejkode                  string   `json:"vejkode"`
+	Husnr                    string   `json:"husnr"`
+	SupplerendeBynavn        string   `json:"supplerendebynavn"`
+	Postnr                   string   `json:"postnr"`
+	Ejerlavkode              int      `json:"ejerlavkode"`
+	Matrikelnr               string   `json:"matrikelnr"`
+	EsrEjendomsNr            string   `json:"esrejendomsnr"`
+	Nøjagtighed              string   `json:"nøjagtighed"`
+	Kilde                    int      `json:"kilde"`
+	Tekniskstandard          string   `json:"tekniskstandard"`
+	Tekstretning             float64  `json:"tekstretning"`
+	Etrs89Øst                *float64 `json:"etrs89koordinat_øst"`
+	Etrs89Nord               *float64 `json:"etrs89koordinat_nord"`
+	Adressepunktændringsdato AwsTime  `json:"adressepunktændringsdato"`
+	DDKNM100                 string   `json:"ddkn_m100"`
+	DDKNKm1                  string   `json:"ddkn_km1"`
+	DDKNKm10                 string   `json:"ddkn_km10"`
+}
+
+// replikeringPostnummer is the replication format of a postnummer.
+type replikeringPostnummer struct {
+	Nr   string `json:"nr"`
+	Navn string `json:"navn"`
+}
+
+// replikeringVejstykke is the replication format of a vejstykke.
+type replikeringVejstykke struct {
+	Kommunekode      string  `json:"kommunekode"`
+	Kode             string  `json:"kode"`
+	Oprettet         AwsTime `json:"oprettet"`
+	Ændret           AwsTime `json:"ændret"`
+	Vejnavn          string  `json:"vejnavn"`
+	Adresseringsnavn string  `json:"adresseringsnavn"`
+}
+
+// replikeringData will decode the replication format in b into dst.
+func replikeringData(dst interface{}, b []byte) error {
+	switch v := dst.(type) {
+	case *Adresse:
+		var r replikeringAdresse
+		if err := json.Unmarshal(b, &r); err != nil {
+			return err
+		}
+		*v = Adresse{ID: r.ID, Status: r.Status, Etage: r.Etage, Dør: r.Dør}
+		v.Historik = Historik{Oprettet: r.Oprettet, Ændret: r.Ændret}
+		v.Adgangsadresse.ID = r.AdgangsadresseID
+	case *AdgangsAdresse:
+		var r replikeringAdgangsAdresse
+		if err := json.Unmarshal(b, &r); err != nil {
+			return err
+		}
+		*v = AdgangsAdresse{ID: r.ID, Status: r.Status, Husnr: r.Husnr, SupplerendeBynavn: r.SupplerendeBynavn}
+		v.Historik = Historik{Oprettet: r.Oprettet, Ændret: r.Ændret}
+		v.Kommune.Kode = r.Kommunekode
+		v.Vejstykke.Kode = r.Vejkode
+		v.Postnummer.Nr = r.Postnr
+		v.Ejerlav.Kode = r.Ejerlavkode
+		v.Matrikelnr = r.Matrikelnr
+		v.EsrEjendomsNr = r.EsrEjendomsNr
+		v.Adgangspunkt.Nøjagtighed = r.Nøjagtighed
+		v.Adgangspunkt.Kilde = r.Kilde
+		v.Adgangspunkt.Tekniskstandard = r.Tekniskstandard
+		v.Adgangspunkt.Tekstretning = r.Tekstretning
+		v.Adgangspunkt.Ændret = r.Adressepunktændringsdato
+		if r.Etrs89Øst != nil && r.Etrs89Nord != nil {
+			e := ETRS89Point(*r.Etrs89Øst, *r.Etrs89Nord)
+			v.Adgangspunkt.Etrs89Koordinater = []float64{e.X, e.Y}
+			if w, err := e.Transform(WGS84); err == nil {
+				v.Adgangspunkt.Koordinater = []float64{w.X, w.Y}
+			}
+		}
+		v.DDKN = DDKN{M100: r.DDKNM100, Km1: r.DDKNKm1, Km10: r.DDKNKm10}
+	case *Postnummer:
+		var r replikeringPostnummer
+		if err := json.Unmarshal(b, &r); err != nil {
+			return err
+		}
+		*v = Postnummer{Nr: r.Nr, Navn: r.Navn}
+	case *Vejstykke:
+		var r replikeringVejstykke
+		if err := json.Unmarshal(b, &r); err != nil {
+			return err
+		}
+		*v = Vejstykke{Kode: r.Kode, Navn: r.Vejnavn, Adresseringsnavn: r.Adresseringsnavn}
+		v.Historik = Historik{Oprettet: r.Oprettet, Ændret: r.Ændret}
+		v.Kommune.Kode = r.Kommunekode
+	default:
+		return fmt.Errorf("unknown replication type %T", dst)
+	}
+	return nil
+}
+
+// replikeringFormat will convert src to the replication format.
+func replikeringFormat(src interface{}) (interface{}, error) {
+	switch v := src.(type) {
+	case *Adresse:
+		return replikeringAdresse{
+			ID:               v.ID,
+			Status:           v.Status,
+			Oprettet:         v.Historik.Oprettet,
+			Ændret:           v.Historik.Ændret,
+			AdgangsadresseID: v.Adgangsadresse.ID,
+			Etage:            v.Etage,
+			Dør:              v.Dør,
+		}, nil
+	case *AdgangsAdresse:
+		r := replikeringAdgangsAdresse{
+			ID:                       v.ID,
+			Status:                   v.Status,
+			Oprettet:                 v.Historik.Oprettet,
+			Ændret:                   v.Historik.Ændret,
+			Kommunekode:              v.Kommune.Kode,
+			Vejkode:                  v.Vejstykke.Kode,
+			Husnr:                    v.Husnr,
+			SupplerendeBynavn:        v.SupplerendeBynavn,
+			Postnr:                   v.Postnummer.Nr,
+			Ejerlavkode:              v.Ejerlav.Kode,
+			Matrikelnr:               v.Matrikelnr,
+			EsrEjendomsNr:            v.EsrEjendomsNr,
+			Nøjagtighed:              v.Adgangspunkt.Nøjagtighed,
+			Kilde:                    v.Adgangspunkt.Kilde,
+			Tekniskstandard:          v.Adgangspunkt.Tekniskstandard,
+			Tekstretning:             v.Adgangspunkt.Tekstretning,
+			Adressepunktændringsdato: v.Adgangspunkt.Ændret,
+			DDKNM100:                 v.DDKN.M100,
+			DDKNKm1:                  v.DDKN.Km1,
+			DDKNKm10:                 v.DDKN.Km10,
+		}
+		if e, ok := v.Adgangspunkt.ETRS89(); ok {
+			r.Etrs89Øst, r.Etrs89Nord = &e.X, &e.Y
+		}
+		return r, nil
+	case *Postnummer:
+		return replikeringPostnummer{Nr: v.Nr, Navn: v.Navn}, nil
+	case *Vejstykke:
+		return replikeringVejstykke{
+			Kommunekode:      v.Kommune.Kode,
+			Kode:             v.Kode,
+			Oprettet:         v.Historik.Oprettet,
+			Ændret:           v.Historik.Ændret,
+			Vejnavn:          v.Navn,
+			Adresseringsnavn: v.Adresseringsnavn,
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown replication type %T", src)
+}
+
+// MarshalJSON will encode the event in the DAWA replication format.
+func (h Haendelse[T]) MarshalJSON() ([]byte, error) {
+	data, err := replikeringFormat(&h.Data)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		Operation     string      `json:"operation"`
+		Tidspunkt     time.Time   `json:"tidspunkt"`
+		Sekvensnummer int64       `json:"sekvensnummer"`
+		Data          interface{} `json:"data"`
+	}{h.Operation, h.Tidspunkt, h.Sekvensnummer, data})
+}
+
+// UnmarshalJSON will decode an event from the DAWA replication format.
+func (h *Haendelse[T]) UnmarshalJSON(b []byte) error {
+	var e struct {
+		Operation     string          `json:"operation"`
+		Tidspunkt     time.Time       `json:"tidspunkt"`
+		Sekvensnummer int64           `json:"sekvensnummer"`
+		Data          json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(b, &e); err != nil {
+		return err
+	}
+	h.Operation = e.Operation
+	h.Tidspunkt = e.Tidspunkt
+	h.Sekvensnummer = e.Sekvensnummer
+	return replikeringData(&h.Data, e.Data)
+}
+
+// haendelsePath returns the path of the events for T.
+func haendelsePath[T HaendelseData]() string {
+	var v T
+	switch any(v).(type) {
+	case Adresse:
+		return "/replikering/adresser/haendelser"
+	case AdgangsAdresse:
+		return "/replikering/adgangsadresser/haendelser"
+	case Postnummer:
+		return "/replikering/postnumre/haendelser"
+	case Vejstykke:
+		return "/replikering/vejstykker/haendelser"
+	}
+	panic("unknown replication type")
+}
+
+// SenesteSekvensnummer returns the sequence number of the latest replication event.
+//
+// See documentation at http://dawa.aws.dk/replikeringdok
+func SenesteSekvensnummer() (*Sekvensnummer, error) {
+	return DefaultClient.SenesteSekvensnummerContext(context.Background())
+}
+
+// SenesteSekvensnummer returns the sequence number of the latest replication event using this client.
+//
+// See documentation at http://dawa.aws.dk/replikeringdok
+func (c *Client) SenesteSekvensnummer() (*Sekvensnummer, error) {
+	return c.SenesteSekvensnummerContext(context.Background())
+}
+
+// SenesteSekvensnummerContext returns the sequence number like SenesteSekvensnummer().
+// If ctx is cancelled, the request is aborted and the context error is returned.
+func (c *Client) SenesteSekvensnummerContext(ctx context.Context) (*Sekvensnummer, error) {
+	q := c.newQuery("/replikering/senestesekvensnummer").query
+	resp, err := q.RequestContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Close()
+	b, err := ioutil.ReadAll(resp)
+	if err != nil {
+		return nil, err
+	}
+	var s Sekvensnummer
+	err = json.Unmarshal(b, &s)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// HaendelseQuery is a query for replication events of type T.
+// Use HaendelseQueryOf() to get an initialized object.
+// Example:
+//			// Get all changes to adgangsadresser after sequence number 1000
+//			events, err := dawa.HaendelseQueryOf[dawa.AdgangsAdresse]().SekvensnummerFra(1001).All()
+type HaendelseQuery[T HaendelseData] struct {
+	query
+}
+
+// HaendelseQueryOf returns a new query for replication events of type T.
+//
+// See documentation at http://dawa.aws.dk/replikeringdok
+func HaendelseQueryOf[T HaendelseData]() *HaendelseQuery[T] {
+	return ClientHaendelseQueryOf[T](DefaultClient)
+}
+
+// ClientHaendelseQueryOf returns a new query for replication events of type T using the supplied client.
+//
+// See documentation at http://dawa.aws.dk/replikeringdok
+func ClientHaendelseQueryOf[T HaendelseData](c *Client) *HaendelseQuery[T] {
+	return &HaendelseQuery[T]{query: c.newQuery(haendelsePath[T]()).query}
+}
+
+// SekvensnummerFra will add a parameter for 'sekvensnummerfra' to the HaendelseQuery.
+//
+// Returner hændelser med sekvensnummer større eller lig det angivne.
+func (q *HaendelseQuery[T]) SekvensnummerFra(n int64) *HaendelseQuery[T] {
+	q.add(&textQuery{Name: "sekvensnummerfra", Values: []string{strconv.FormatInt(n, 10)}, Multi: false, Null: false})
+	return q
+}
+
+// SekvensnummerTil will add a parameter for 'sekvensnummertil' to the HaendelseQuery.
+//
+// Returner hændelser med sekvensnummer mindre end eller lig det angivne.
+func (q *HaendelseQuery[T]) SekvensnummerTil(n int64) *HaendelseQuery[T] {
+	q.add(&textQuery{Name: "sekvensnummertil", Values: []string{strconv.FormatInt(n, 10)}, Multi: false, Null: false})
+	return q
+}
+
+// ID will add a parameter for 'id' to the HaendelseQuery.
+//
+// Returner kun hændelser for objektet med det angivne id.
+// Kan kun anvendes for adresser og adgangsadresser.
+func (q *HaendelseQuery[T]) ID(s string) *HaendelseQuery[T] {
+	q.add(&textQuery{Name: "id", Values: []string{s}, Multi: false, Null: false})
+	return q
+}
+
+// Iter will return an iterator that allows you to read the events
+// one by one, ordered by sequence number.
+func (q HaendelseQuery[T]) Iter() (*Iter[Haendelse[T]], error) {
+	return q.IterContext(context.Background())
+}
+
+// IterContext will return an iterator like Iter().
+// If ctx is cancelled, the request is aborted and
+// the iterator will return the context error.
+func (q HaendelseQuery[T]) IterContext(ctx context.Context) (*Iter[Haendelse[T]], error) {
+	return iterQuery[Haendelse[T]](ctx, q.query)
+}
+
+// All returns all events as an array.
+func (q HaendelseQuery[T]) All() ([]Haendelse[T], error) {
+	return q.AllContext(context.Background())
+}
+
+// AllContext returns all events as an array like All().
+// If ctx is cancelled, the request is aborted and the context error is returned.
+func (q HaendelseQuery[T]) AllContext(ctx context.Context) ([]Haendelse[T], error) {
+	return allQuery[Haendelse[T]](ctx, q.query)
+}
+
+// SyncHaendelser will call fn with all events of type T with a sequence number after 'from',
+// up to the latest sequence number.
+//
+// The sequence number of the last event that was handled is returned.
+// Store this and use it as 'from' on the next sync.
+// If there are no new events, 'from' is returned.
+//
+// If fn returns an error, the sync is stopped and the error is returned
+// along with the sequence number of the last event that was successfully handled.
+//
+// Example:
+//			last, err := dawa.SyncHaendelser(ctx, last, func(h dawa.AdresseHaendelse) error {
+//				switch h.Operation {
+//				case dawa.OperationInsert, dawa.OperationUpdate:
+//					return store.Put(h.Data)
+//				case dawa.OperationDelete:
+//					return store.Delete(h.Data.ID)
+//				}
+//				return nil
+//			})
+func SyncHaendelser[T HaendelseData](ctx context.Context, from int64, fn func(Haendelse[T]) error) (int64, error) {
+	return ClientSyncHaendelser(ctx, DefaultClient, from, fn)
+}
+
+// ClientSyncHaendelser will sync events like SyncHaendelser() using the supplied client.
+func ClientSyncHaendelser[T HaendelseData](ctx context.Context, c *Client, from int64, fn func(Haendelse[T]) error) (int64, error) {
+	latest, err := c.SenesteSekvensnummerContext(ctx)
+	if err != nil {
+		return from, err
+	}
+	if latest.Sekvensnummer <= from {
+		return from, nil
+	}
+	iter, err := ClientHaendelseQueryOf[T](c).SekvensnummerFra(from + 1).SekvensnummerTil(latest.Sekvensnummer).IterContext(ctx)
+	if err != nil {
+		return from, err
+	}
+	defer iter.Close()
+	last := from
+	for {
+		h, err := iter.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return last, err
+		}
+		if err := fn(*h); err != nil {
+			return last, err
+		}
+		last = h.Sekvensnummer
+	}
+	// All events up to latest have been handled, even if the last ones
+	// were for other types.
+	return latest.Sekvensnummer, nil
+}
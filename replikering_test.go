@@ -0,0 +1,215 @@
+package dawa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+var haendelser_adgangsadresser_json_input = `[
+{
+  "operation": "insert",
+  "tidspunkt": "2014-05-05T19:07:48.577Z",
+  "sekvensnummer": 1001,
+  "data": {
+    "id": "0a3f507a-e179-32b8-e044-0003ba298018",
+    "status": 1,
+    "oprettet": "2000-02-05T20:25:15.000",
+    "ændret": "2009-11-24T03:15:31.000",
+    "ikrafttrædelsesdato": null,
+    "kommunekode": "0101",
+    "vejkode": "0004",
+    "husnr": "2",
+    "supplerendebynavn": null,
+    "postnr": "2100",
+    "ejerlavkode": 2000163,
+    "matrikelnr": "879",
+    "esrejendomsnr": "78935",
+    "etrs89koordinat_øst": 725025.5,
+    "etrs89koordinat_nord": 6166305.42,
+    "nøjagtighed": "A",
+    "kilde": 2,
+    "husnummerkilde": 1,
+    "tekniskstandard": "TN",
+    "tekstretning": 200,
+    "adressepunktændringsdato": "2002-04-08T00:00:00.000",
+    "ddkn_m100": "100m_61663_7250",
+    "ddkn_km1": "1km_6166_725",
+    "ddkn_km10": "10km_616_72"
+  }
+},
+{
+  "operation": "delete",
+  "tidspunkt": "2014-05-06T10:00:00.000Z",
+  "sekvensnummer": 1003,
+  "data": {
+    "id": "0a3f507a-e17a-32b8-e044-0003ba298018",
+    "status": 1,
+    "oprettet": "2000-02-05T20:25:15.000",
+    "ændret": "2009-11-24T03:15:31.000",
+    "kommunekode": "0101",
+    "vejkode": "0004",
+    "husnr": "4",
+    "postnr": "2100"
+  }
+}
+]`
+
+func TestHaendelseQueryURL(t *testing.T) {
+	tests := []qb{
+		{HaendelseQueryOf[Adresse]().URL(), DefaultHost + "/replikering/adresser/haendelser"},
+		{HaendelseQueryOf[AdgangsAdresse]().SekvensnummerFra(10).SekvensnummerTil(20).URL(), DefaultHost + "/replikering/adgangsadresser/haendelser?sekvensnummerfra=10&sekvensnummertil=20"},
+		{HaendelseQueryOf[Postnummer]().URL(), DefaultHost + "/replikering/postnumre/haendelser"},
+		{HaendelseQueryOf[Vejstykke]().ID("abc").URL(), DefaultHost + "/replikering/vejstykker/haendelser?id=abc"},
+	}
+	for _, q := range tests {
+		if q.Got != q.Expected {
+			t.Fatalf("Unexpected URL:\n     Was:\t%s\nExpected:\t%s", q.Got, q.Expected)
+		}
+	}
+}
+
+func replikeringServer(t *testing.T, latest string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/replikering/senestesekvensnummer":
+			w.Write([]byte(`{"sekvensnummer":` + latest + `,"tidspunkt":"2014-05-06T10:00:00.000Z"}`))
+		case "/replikering/adgangsadresser/haendelser":
+			if f := r.URL.Query().Get("sekvensnummerfra"); f != "1001" {
+				t.Errorf("Unexpected sekvensnummerfra: %s", f)
+			}
+			if f := r.URL.Query().Get("sekvensnummertil"); f != latest {
+				t.Errorf("Unexpected sekvensnummertil: %s", f)
+			}
+			w.Write([]byte(haendelser_adgangsadresser_json_input))
+		default:
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestHaendelseQuery(t *testing.T) {
+	ts := replikeringServer(t, "1004")
+	defer ts.Close()
+	c := &Client{BaseURL: ts.URL}
+
+	s, err := c.SenesteSekvensnummer()
+	if err != nil || s.Sekvensnummer != 1004 {
+		t.Fatalf("SenesteSekvensnummer: %+v, %v", s, err)
+	}
+
+	all, err := ClientHaendelseQueryOf[AdgangsAdresse](c).SekvensnummerFra(1001).SekvensnummerTil(1004).All()
+	if err != nil {
+		t.Fatalf("All: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(all))
+	}
+	h := all[0]
+	if h.Operation != OperationInsert || h.Sekvensnummer != 1001 || h.Tidspunkt.IsZero() {
+		t.Fatalf("Unexpected event: %+v", h)
+	}
+	a := h.Data
+	if a.ID != "0a3f507a-e179-32b8-e044-0003ba298018" || a.Kommune.Kode != "0101" || a.Vejstykke.Kode != "0004" || a.Postnummer.Nr != "2100" {
+		t.Fatalf("Unexpected data: %+v", a)
+	}
+	if a.Ejerlav.Kode != 2000163 || a.DDKN.Km10 != "10km_616_72" || a.Adgangspunkt.Kilde != 2 {
+		t.Fatalf("Unexpected data: %+v", a)
+	}
+	if a.Historik.Oprettet.Time().Year() != 2000 {
+		t.Fatalf("Unexpected oprettet: %v", a.Historik.Oprettet.Time())
+	}
+	if e, ok := a.Adgangspunkt.ETRS89(); !ok || e != ETRS89Point(725025.5, 6166305.42) {
+		t.Fatalf("Unexpected ETRS89 koordinater: %v", a.Adgangspunkt.Etrs89Koordinater)
+	}
+	if w, ok := a.Adgangspunkt.WGS84(); !ok || w.X < 12.5 || w.X > 12.6 || w.Y < 55.5 || w.Y > 55.7 {
+		t.Fatalf("Unexpected WGS84 koordinater: %v", a.Adgangspunkt.Koordinater)
+	}
+	if all[1].Operation != OperationDelete || all[1].Data.Husnr != "4" {
+		t.Fatalf("Unexpected event: %+v", all[1])
+	}
+	if len(all[1].Data.Adgangspunkt.Koordinater) != 0 {
+		t.Fatalf("Unexpected koordinater: %v", all[1].Data.Adgangspunkt.Koordinater)
+	}
+
+	// The koordinater are kept when the events are written in the replication format.
+	b, err := json.Marshal(h)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var out AdgangsAdresseHaendelse
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	got := out.Data.Adgangspunkt
+	if !reflect.DeepEqual(got.Koordinater, a.Adgangspunkt.Koordinater) || !reflect.DeepEqual(got.Etrs89Koordinater, a.Adgangspunkt.Etrs89Koordinater) {
+		t.Fatalf("Round trip failed:\n%+v\n%+v", a.Adgangspunkt, got)
+	}
+}
+
+func TestSyncHaendelser(t *testing.T) {
+	ts := replikeringServer(t, "1004")
+	defer ts.Close()
+	c := &Client{BaseURL: ts.URL}
+
+	var got []int64
+	last, err := ClientSyncHaendelser(context.Background(), c, 1000, func(h AdgangsAdresseHaendelse) error {
+		got = append(got, h.Sekvensnummer)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if last != 1004 || len(got) != 2 {
+		t.Fatalf("Expected 2 events and last 1004, got %v and %d", got, last)
+	}
+
+	// Stop on error
+	stop := errors.New("stop")
+	last, err = ClientSyncHaendelser(context.Background(), c, 1000, func(h AdgangsAdresseHaendelse) error {
+		if h.Sekvensnummer == 1003 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || last != 1001 {
+		t.Fatalf("Expected stop at 1001, got %d, %v", last, err)
+	}
+
+	// Nothing new
+	last, err = ClientSyncHaendelser(context.Background(), c, 1004, func(h AdgangsAdresseHaendelse) error {
+		t.Fatal("Unexpected event")
+		return nil
+	})
+	if err != nil || last != 1004 {
+		t.Fatalf("Expected 1004, got %d, %v", last, err)
+	}
+}
+
+func TestHaendelseMarshal(t *testing.T) {
+	in := VejstykkeHaendelse{Operation: OperationUpdate, Sekvensnummer: 42}
+	in.Tidspunkt = MustParseTime("2014-05-06T10:00:00.000").Time().UTC()
+	in.Data.Kode = "0004"
+	in.Data.Navn = "Abel Cathrines Gade"
+	in.Data.Kommune.Kode = "0101"
+	in.Data.Historik.Oprettet = MustParseTime("2000-02-05T20:25:15.000")
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var out VejstykkeHaendelse
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !out.Tidspunkt.Equal(in.Tidspunkt) || out.Data.Navn != in.Data.Navn || out.Data.Kommune.Kode != "0101" {
+		t.Fatalf("Round trip failed:\n%+v\n%+v", in, out)
+	}
+	if !out.Data.Historik.Oprettet.Time().Equal(in.Data.Historik.Oprettet.Time()) {
+		t.Fatalf("Oprettet: got %v, expected %v", out.Data.Historik.Oprettet.Time(), in.Data.Historik.Oprettet.Time())
+	}
+}
@@ -0,0 +1,157 @@
+package dawa
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how requests that fail with a transient error are retried.
+// Set it as Retry on a Client to enable retries.
+//
+// Requests are retried on timeouts, refused or reset connections,
+// connections closed before the response was read, and on responses with status
+// 408 (Request Timeout), 429 (Too Many Requests), 500, 502, 503 and 504.
+// Other errors, as well as cancelled contexts, are returned at once.
+//
+// Between attempts the client will wait with exponential backoff and jitter,
+// unless the server specifies how long to wait using a 'Retry-After' header.
+//
+// Example:
+//			client := dawa.NewClient(nil)
+//			client.Retry = &dawa.RetryPolicy{
+//				MaxAttempts: 5,
+//				OnRetry: func(r dawa.RetryInfo) {
+//					log.Printf("Retrying %s in %v: %v", r.URL, r.Wait, r.Err)
+//				},
+//			}
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first.
+	// Values less than 2 disables retries.
+	MaxAttempts int
+
+	// MinBackoff is the wait before the first retry. It is doubled for every following attempt.
+	// If 0, 500 milliseconds is used.
+	MinBackoff time.Duration
+
+	// MaxBackoff is the maximum wait between attempts.
+	// If 0, 30 seconds is used.
+	MaxBackoff time.Duration
+
+	// MaxRetryAfter is the maximum wait accepted from a 'Retry-After' header.
+	// If the server asks for a longer wait, the request is not retried.
+	// If 0, there is no limit.
+	MaxRetryAfter time.Duration
+
+	// OnRetry is called before waiting for each retry, if not nil.
+	OnRetry func(RetryInfo)
+}
+
+// RetryInfo contains information about a request that is about to be retried.
+type RetryInfo struct {
+	URL        string        // The URL of the request.
+	Attempt    int           // The attempt that failed, starting at 1.
+	StatusCode int           // The HTTP status code of the failed attempt, 0 if the request failed.
+	Err        error         // The error of the failed attempt, nil if a response was received.
+	Wait       time.Duration // The time that will be waited before the next attempt.
+}
+
+const (
+	defaultMinBackoff = 500 * time.Millisecond
+	defaultMaxBackoff = 30 * time.Second
+)
+
+// backoff returns the time to wait after the specified failed attempt.
+// Full backoff is MinBackoff * 2^(attempt-1), limited by MaxBackoff.
+// The returned value is randomly chosen between half and full backoff.
+func (r RetryPolicy) backoff(attempt int) time.Duration {
+	minB, maxB := r.MinBackoff, r.MaxBackoff
+	if minB <= 0 {
+		minB = defaultMinBackoff
+	}
+	if maxB <= 0 {
+		maxB = defaultMaxBackoff
+	}
+	wait := minB
+	for i := 1; i < attempt && wait < maxB; i++ {
+		wait *= 2
+	}
+	if wait > maxB {
+		wait = maxB
+	}
+	half := int64(wait / 2)
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// retryableStatus returns true if the status code indicates a transient error.
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests,
+		http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryableError returns true if the error of a failed request is likely transient.
+func retryableError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	// http.Client.Do wraps all errors in *url.Error, which is a net.Error,
+	// so only the error it wraps is checked.
+	var uerr *url.Error
+	if errors.As(err, &uerr) {
+		err = uerr.Err
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var nerr net.Error
+	return errors.As(err, &nerr) && nerr.Timeout()
+}
+
+// parseRetryAfter parses the value of a 'Retry-After' header.
+// The value can be given in seconds or as a HTTP date.
+// Returns false if the value could not be parsed.
+func parseRetryAfter(s string, now time.Time) (time.Duration, bool) {
+	if s == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(s); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	t, err := http.ParseTime(s)
+	if err != nil {
+		return 0, false
+	}
+	d := t.Sub(now)
+	if d < 0 {
+		d = 0
+	}
+	return d, true
+}
+
+// sleepContext waits for d, or until ctx is cancelled.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,170 @@
+package dawa
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientRetry(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		switch n {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte(postnumre_json_input))
+		}
+	}))
+	defer ts.Close()
+
+	var retries []RetryInfo
+	c := &Client{BaseURL: ts.URL}
+	c.Retry = &RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+		OnRetry:     func(r RetryInfo) { retries = append(retries, r) },
+	}
+	all, err := c.NewPostnrQuery().All()
+	if err != nil {
+		t.Fatalf("All: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(all))
+	}
+	if len(retries) != 2 {
+		t.Fatalf("Expected 2 retries, got %d", len(retries))
+	}
+	if retries[0].StatusCode != http.StatusTooManyRequests || retries[0].Wait != 0 || retries[0].Attempt != 1 {
+		t.Fatalf("Unexpected first retry: %+v", retries[0])
+	}
+	if retries[1].StatusCode != http.StatusServiceUnavailable || retries[1].Attempt != 2 {
+		t.Fatalf("Unexpected second retry: %+v", retries[1])
+	}
+}
+
+func TestClientRetryExhausted(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(`{"type":"InternalError","title":"Bad gateway"}`))
+	}))
+	defer ts.Close()
+
+	c := &Client{BaseURL: ts.URL, Retry: &RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond}}
+	_, err := c.NewPostnrQuery().First()
+	if _, ok := err.(RequestError); !ok {
+		t.Fatalf("Expected RequestError, got %T: %v", err, err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Fatalf("Expected 2 calls, got %d", n)
+	}
+}
+
+func TestClientRetryNotRetryable(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"type":"QueryParameterFormatError","title":"Invalid parameter"}`))
+	}))
+	defer ts.Close()
+
+	c := &Client{BaseURL: ts.URL, Retry: &RetryPolicy{MaxAttempts: 5, MinBackoff: time.Millisecond}}
+	_, err := c.NewPostnrQuery().First()
+	if _, ok := err.(RequestError); !ok {
+		t.Fatalf("Expected RequestError, got %T: %v", err, err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("Expected 1 call, got %d", n)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2015, 10, 21, 7, 28, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"", 0, false},
+		{"120", 2 * time.Minute, true},
+		{"-1", 0, false},
+		{"Wed, 21 Oct 2015 07:28:30 GMT", 30 * time.Second, true},
+		{"Wed, 21 Oct 2015 07:27:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+	for _, test := range tests {
+		got, ok := parseRetryAfter(test.in, now)
+		if got != test.want || ok != test.ok {
+			t.Fatalf("parseRetryAfter(%q): got (%v, %v), expected (%v, %v)", test.in, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	r := RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for attempt, full := range []time.Duration{0, 100, 200, 400, 800, 1000, 1000} {
+		if attempt == 0 {
+			continue
+		}
+		full *= time.Millisecond
+		for i := 0; i < 100; i++ {
+			got := r.backoff(attempt)
+			if got < full/2 || got > full {
+				t.Fatalf("backoff(%d): %v outside [%v, %v]", attempt, got, full/2, full)
+			}
+		}
+	}
+}
+
+func TestClientRetryPermanentErrors(t *testing.T) {
+	tls := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	// The failed handshakes are expected.
+	tls.Config.ErrorLog = log.New(io.Discard, "", 0)
+	tls.StartTLS()
+	defer tls.Close()
+	for _, base := range []string{"ftp://dawa.aws.dk", tls.URL} {
+		var retries int
+		c := &Client{BaseURL: base}
+		c.Retry = &RetryPolicy{
+			MaxAttempts: 5,
+			MinBackoff:  time.Millisecond,
+			OnRetry:     func(RetryInfo) { retries++ },
+		}
+		_, err := c.NewPostnrQuery().First()
+		if err == nil {
+			t.Fatalf("%s: expected an error", base)
+		}
+		if retries != 0 {
+			t.Fatalf("%s: expected no retries, got %d: %v", base, retries, err)
+		}
+	}
+}
+
+func TestClientRetryConnRefused(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ts.Close()
+	var retries int
+	c := &Client{BaseURL: ts.URL}
+	c.Retry = &RetryPolicy{
+		MaxAttempts: 3,
+		MinBackoff:  time.Millisecond,
+		OnRetry:     func(RetryInfo) { retries++ },
+	}
+	if _, err := c.NewPostnrQuery().First(); err == nil {
+		t.Fatal("expected an error")
+	}
+	if retries != 2 {
+		t.Fatalf("expected 2 retries, got %d", retries)
+	}
+}
@@ -0,0 +1,203 @@
+package dawa
+
+import (
+	"context"
+	"strconv"
+)
+
+// VejstykkeQuery is a new query for 'vejstykke' objects for searching DAWA.
+// Use NewVejstykkeQuery() or NewVejstykkeComplete() to get an initialized object.
+// Example:
+//			// Search for "Rødkildevej" in København
+//			item, err := dawa.NewVejstykkeQuery().Navn("Rødkildevej").Kommunekode("0101").First()
+//
+//			// If err is nil, we go a result
+//			if err == nil {
+//				fmt.Printf("Got item:%+v\n", item)
+//			}
+type VejstykkeQuery struct {
+	queryGeoJSON
+}
+
+// NewVejstykkeQuery returns a new query for 'vejstykke' objects for searching DAWA.
+//
+// See documentation at http://dawa.aws.dk/vejstykkedok#vejstykkesoegning
+func NewVejstykkeQuery() *VejstykkeQuery {
+	return DefaultClient.NewVejstykkeQuery()
+}
+
+// NewVejstykkeComplete returns a new autocomplete query for 'vejstykke' objects for searching DAWA.
+//
+// See documentation at http://dawa.aws.dk/vejstykkedok#vejstykkeautocomplete
+func NewVejstykkeComplete() *VejstykkeQuery {
+	return DefaultClient.NewVejstykkeComplete()
+}
+
+// NewVejstykkeQuery returns a new query for 'vejstykke' objects for searching DAWA using this client.
+//
+// See documentation at http://dawa.aws.dk/vejstykkedok#vejstykkesoegning
+func (c *Client) NewVejstykkeQuery() *VejstykkeQuery {
+	return &VejstykkeQuery{queryGeoJSON: c.newQuery("/vejstykker")}
+}
+
+// NewVejstykkeComplete returns a new autocomplete query for 'vejstykke' objects for searching DAWA using this client.
+//
+// See documentation at http://dawa.aws.dk/vejstykkedok#vejstykkeautocomplete
+func (c *Client) NewVejstykkeComplete() *VejstykkeQuery {
+	return &VejstykkeQuery{queryGeoJSON: c.newQuery("/vejstykker/autocomplete")}
+}
+
+// GetVejstykke will return a single Vejstykke with the specified kommunekode and vejkode.
+// Will return (nil, io.EOF) if there is no results.
+func GetVejstykke(kommunekode, kode string) (*Vejstykke, error) {
+	return NewVejstykkeQuery().Kommunekode(kommunekode).Kode(kode).First()
+}
+
+// Iter will return an iterator that allows you to read the results
+// one by one.
+//
+// An example:
+//			iter, err := dawa.NewVejstykkeQuery().Postnr("2400").Iter()
+//			if err != nil {
+// 				panic(err)
+// 			}
+//
+//			for {
+//				a, err := iter.Next()
+//				if err == io.EOF {
+// 					iter.Close()
+//					break  // we are finished
+//				}
+//				if err != nil {
+//					panic(err)
+//				}
+// 				fmt.Printf("%+v\n", a)
+//			}
+//		}
+func (q VejstykkeQuery) Iter() (*VejstykkeIter, error) {
+	return q.IterContext(context.Background())
+}
+
+// IterContext will return an iterator like Iter().
+// If ctx is cancelled, the request is aborted and
+// the iterator will return the context error.
+func (q VejstykkeQuery) IterContext(ctx context.Context) (*VejstykkeIter, error) {
+	return iterQuery[Vejstykke](ctx, q.query)
+}
+
+// All returns all results as an array.
+func (q VejstykkeQuery) All() ([]Vejstykke, error) {
+	return q.AllContext(context.Background())
+}
+
+// AllContext returns all results as an array like All().
+// If ctx is cancelled, the request is aborted and the context error is returned.
+func (q VejstykkeQuery) AllContext(ctx context.Context) ([]Vejstykke, error) {
+	return allQuery[Vejstykke](ctx, q.query)
+}
+
+// First will return the first result from a query.
+// Note the entire query is executed, so only use this if you expect a few results.
+//
+// Will return (nil, io.EOF) if there is no results.
+func (q VejstykkeQuery) First() (*Vejstykke, error) {
+	return q.FirstContext(context.Background())
+}
+
+// FirstContext will return the first result from a query like First().
+// If ctx is cancelled, the request is aborted and the context error is returned.
+func (q VejstykkeQuery) FirstContext(ctx context.Context) (*Vejstykke, error) {
+	return firstQuery[Vejstykke](ctx, q.query)
+}
+
+// Paginate will make Iter() and All() fetch results page by page.
+// A new page is requested when all results of the previous page have been read.
+// Paging stops when a page contains less than pageSize results.
+// If maxResults is > 0, no more than maxResults will be returned.
+//
+// Any 'side' and 'per_side' parameters will be replaced when paginating.
+//
+// See http://dawa.aws.dk/generelt#paginering
+func (q *VejstykkeQuery) Paginate(pageSize, maxResults int) *VejstykkeQuery {
+	q.paging(pageSize, maxResults)
+	return q
+}
+
+// Kode will add a parameter for 'kode' to the VejstykkeQuery.
+//
+// Vejkode. 4 cifre. (Flerværdisøgning mulig).
+//
+// See documentation at http://dawa.aws.dk/vejstykkedok#vejstykkesoegning
+func (q *VejstykkeQuery) Kode(s ...string) *VejstykkeQuery {
+	q.add(&textQuery{Name: "kode", Values: s, Multi: true, Null: false})
+	return q
+}
+
+// Kommunekode will add a parameter for 'kommunekode' to the VejstykkeQuery.
+//
+// Kommunekode. 4 cifre. Eksempel: 0101 for Københavns kommune. (Flerværdisøgning mulig).
+//
+// See documentation at http://dawa.aws.dk/vejstykkedok#vejstykkesoegning
+func (q *VejstykkeQuery) Kommunekode(s ...string) *VejstykkeQuery {
+	q.add(&textQuery{Name: "kommunekode", Values: s, Multi: true, Null: false})
+	return q
+}
+
+// Navn will add a parameter for 'navn' to the VejstykkeQuery.
+//
+// Vejnavn. Der skelnes mellem store og små bogstaver. (Flerværdisøgning mulig).
+//
+// See documentation at http://dawa.aws.dk/vejstykkedok#vejstykkesoegning
+func (q *VejstykkeQuery) Navn(s ...string) *VejstykkeQuery {
+	q.add(&textQuery{Name: "navn", Values: s, Multi: true, Null: false})
+	return q
+}
+
+// Postnr will add a parameter for 'postnr' to the VejstykkeQuery.
+//
+// Postnummer. 4 cifre. (Flerværdisøgning mulig).
+//
+// See documentation at http://dawa.aws.dk/vejstykkedok#vejstykkesoegning
+func (q *VejstykkeQuery) Postnr(s ...string) *VejstykkeQuery {
+	q.add(&textQuery{Name: "postnr", Values: s, Multi: true, Null: false})
+	return q
+}
+
+// Q will add a parameter for 'q' to the VejstykkeQuery.
+//
+// Søgetekst. Der søges i vejnavnet. Alle ord i søgeteksten skal matche vejnavnet.
+// Wildcard * er tilladt i slutningen af hvert ord.
+//
+// See documentation at http://dawa.aws.dk/vejstykkedok#vejstykkesoegning
+func (q *VejstykkeQuery) Q(s string) *VejstykkeQuery {
+	q.add(&textQuery{Name: "q", Values: []string{s}, Multi: false, Null: true})
+	return q
+}
+
+// Side will add a parameter for 'side' to the VejstykkeQuery.
+//
+// Angiver hvilken siden som skal leveres. Se Paginering.
+// http://dawa.aws.dk/generelt#paginering
+//
+// See documentation at http://dawa.aws.dk/vejstykkedok#vejstykkesoegning
+func (q *VejstykkeQuery) Side(i int) *VejstykkeQuery {
+	q.add(&textQuery{Name: "side", Values: []string{strconv.Itoa(i)}, Multi: false, Null: true})
+	return q
+}
+
+// PerSide will add a parameter for 'per_side' to the VejstykkeQuery.
+//
+// Antal resultater per side. Se Paginering.
+// http://dawa.aws.dk/generelt#paginering
+//
+// See documentation at http://dawa.aws.dk/vejstykkedok#vejstykkesoegning
+func (q *VejstykkeQuery) PerSide(i int) *VejstykkeQuery {
+	q.add(&textQuery{Name: "per_side", Values: []string{strconv.Itoa(i)}, Multi: false, Null: true})
+	return q
+}
+
+// NoFormat will disable extra whitespace. Always enabled when querying
+func (q *VejstykkeQuery) NoFormat() *VejstykkeQuery {
+	q.add(&textQuery{Name: "noformat", Multi: false, Null: true})
+	return q
+}
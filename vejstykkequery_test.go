@@ -0,0 +1,62 @@
+package dawa
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var VejstykkeURL = []qb{
+	qb{NewVejstykkeQuery().URL(), DefaultHost + "/vejstykker"},
+	qb{NewVejstykkeComplete().URL(), DefaultHost + "/vejstykker/autocomplete"},
+	qb{NewVejstykkeQuery().Kode(multiParam...).URL(), DefaultHost + "/vejstykker?kode=" + multiEncoded + ""},
+	qb{NewVejstykkeQuery().Kommunekode(multiParam...).URL(), DefaultHost + "/vejstykker?kommunekode=" + multiEncoded + ""},
+	qb{NewVejstykkeQuery().Navn(multiParam...).URL(), DefaultHost + "/vejstykker?navn=" + multiEncoded + ""},
+	qb{NewVejstykkeQuery().Postnr(multiParam...).URL(), DefaultHost + "/vejstykker?postnr=" + multiEncoded + ""},
+	qb{NewVejstykkeQuery().Q(singleParam).URL(), DefaultHost + "/vejstykker?q=" + singleEncoded + ""},
+	qb{NewVejstykkeQuery().Side(5).PerSide(100).URL(), DefaultHost + "/vejstykker?side=5&per_side=100"},
+	qb{NewVejstykkeQuery().NoFormat().URL(), DefaultHost + "/vejstykker?noformat="},
+	qb{NewVejstykkeQuery().Kommunekode("0101").Kode("0004").URL(), DefaultHost + "/vejstykker?kommunekode=0101&kode=0004"},
+}
+
+func TestVejstykkeQueryURL(t *testing.T) {
+	for _, q := range VejstykkeURL {
+		if q.Got != q.Expected {
+			t.Fatalf("Unexpected value of parameter:\n     Was:\t%s\nExpected:\t%s", q.Got, q.Expected)
+		}
+	}
+}
+
+func TestVejstykkeQuery(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/vejstykker" {
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("kode") == "0000" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(vejstykker_json_input))
+	}))
+	defer ts.Close()
+
+	c := &Client{BaseURL: ts.URL}
+	all, err := c.NewVejstykkeQuery().Postnr("9000").All()
+	if err != nil {
+		t.Fatalf("All: %v", err)
+	}
+	if len(all) == 0 {
+		t.Fatal("Expected results")
+	}
+	v, err := c.NewVejstykkeQuery().Kommunekode("0563").Kode("9369").First()
+	if err != nil {
+		t.Fatalf("First: %v", err)
+	}
+	if v.Kode != all[0].Kode || v.Navn != all[0].Navn {
+		t.Fatalf("Unexpected first result: %+v", v)
+	}
+	if _, err := c.NewVejstykkeQuery().Kode("0000").First(); err != io.EOF {
+		t.Fatalf("Expected io.EOF, got %v", err)
+	}
+}
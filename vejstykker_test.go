@@ -170,3 +170,51 @@ func TestImportVejstykkerJSON(t *testing.T) {
 		t.Fatalf("ImportVejstykkerJSON: Expected io.EOF, got:%v", err)
 	}
 }
+
+var vejstykker_csv_data = `kommunekode,kode,oprettet,ændret,kommunenavn,navn,adresseringsnavn,navngivenvej_id
+0563,9369,2010-01-17T11:19:52.237,2010-01-17T11:19:52.237,Fanø,Vesten Bavnen,Vesten Bavnen,0b8c10a1-4fa7-4c6b-a2b5-e0c3cb5d9a11
+0563,9379,,,Fanø,Vesten Sandene,Vesten Sandene,
+`
+
+func TestImportVejstykkerCSV(t *testing.T) {
+	expect := []Vejstykke{
+		{
+			Adresseringsnavn: "Vesten Bavnen",
+			Historik:         Historik{Oprettet: MustParseTime("2010-01-17T11:19:52.237"), Ændret: MustParseTime("2010-01-17T11:19:52.237")},
+			Kode:             "9369",
+			Kommune:          KommuneRef{Kode: "0563", Navn: "Fanø"},
+			Navn:             "Vesten Bavnen",
+		},
+		{
+			Adresseringsnavn: "Vesten Sandene",
+			Kode:             "9379",
+			Kommune:          KommuneRef{Kode: "0563", Navn: "Fanø"},
+			Navn:             "Vesten Sandene",
+		},
+	}
+	iter, err := ImportCSV[Vejstykke](bytes.NewBufferString(vejstykker_csv_data), CSVOptions{})
+	if err != nil {
+		t.Fatalf("ImportVejstykkerCSV: %v", err)
+	}
+	for i, e := range expect {
+		item, err := iter.Next()
+		if err != nil {
+			t.Fatalf("ImportVejstykkerCSV, iter.Next(): %v", err)
+		}
+		if !reflect.DeepEqual(*item, e) {
+			t.Fatalf("ImportVejstykkerCSV %d, value mismatch.\nGot:\n%#v\nExpected:\n%#v\n", i, *item, e)
+		}
+	}
+	if _, err := iter.Next(); err != io.EOF {
+		t.Fatalf("ImportVejstykkerCSV: Expected io.EOF, got:%v", err)
+	}
+
+	// Dates must be valid
+	iter, err = ImportCSV[Vejstykke](bytes.NewBufferString("kode,oprettet\n0001,igår\n"), CSVOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := iter.Next(); err == nil || err == io.EOF {
+		t.Fatalf("ImportVejstykkerCSV: Expected error, got:%v", err)
+	}
+}